@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"flag"
@@ -35,6 +36,15 @@ type options struct {
 	followReferral bool
 	srv            string
 	showVersion    bool
+	protocol       string
+	dohURL         string
+	dnssec         bool
+	rdap           bool
+	trace          bool
+	input          string
+	concurrency    int
+	ratePerServer  float64
+	ndjson         bool
 }
 
 func main() {
@@ -45,6 +55,11 @@ func main() {
 		os.Exit(0)
 	}
 
+	if opts.input != "" {
+		handleBatchLookup(opts)
+		return
+	}
+
 	if opts.domain == "" && opts.srv == "" {
 		fmt.Println("Error: domain is required")
 		flag.Usage()
@@ -58,16 +73,7 @@ func main() {
 	}
 
 	// Create client configuration
-	config := &lookup.Config{
-		DNS: &dns.Config{
-			Timeout:        time.Duration(opts.timeout) * time.Second,
-			CustomResolver: opts.customResolver,
-		},
-		WHOIS: &whois.Config{
-			Timeout:        time.Duration(opts.timeout) * time.Second,
-			FollowReferral: opts.followReferral,
-		},
-	}
+	config := buildConfig(opts)
 
 	client, err := lookup.NewClient(config)
 	if err != nil {
@@ -88,12 +94,98 @@ func main() {
 		handleAllLookup(ctx, client, opts)
 	} else if opts.whoisOnly {
 		handleWhoisLookup(ctx, client, opts)
+	} else if opts.dnssec {
+		handleDNSSECValidation(ctx, client, opts)
+	} else if opts.trace {
+		handleTrace(ctx, client, opts)
 	} else {
 		// Default: DNS lookup only for specified record type
 		handleDNSLookup(ctx, client, opts)
 	}
 }
 
+// handleDNSSECValidation runs a DNSSEC-validating lookup instead of a plain
+// one, printing the chain of trust and exiting non-zero on a Bogus result.
+func handleDNSSECValidation(ctx context.Context, client *lookup.Client, opts *options) {
+	recordType := dns.RecordType(strings.ToUpper(opts.recordType))
+
+	result, err := client.DNSValidate(ctx, opts.domain, recordType)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error performing DNS lookup: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.jsonOutput {
+		printJSON(result)
+	} else {
+		printDNSResult(result)
+		fmt.Printf("\nDNSSEC: %s\n", result.AuthenticationStatus)
+		if len(result.ChainTrace) > 0 {
+			fmt.Println("Chain of trust:")
+			for i, step := range result.ChainTrace {
+				fmt.Printf("  %d. %s\n", i+1, step)
+			}
+		}
+	}
+
+	if result.AuthenticationStatus == dns.Bogus {
+		os.Exit(1)
+	}
+}
+
+// buildConfig turns parsed CLI options into the unified lookup.Config shared
+// by every lookup mode, single or batch.
+func buildConfig(opts *options) *lookup.Config {
+	return &lookup.Config{
+		DNS: &dns.Config{
+			Timeout:        time.Duration(opts.timeout) * time.Second,
+			CustomResolver: opts.customResolver,
+			Transports:     protocolTransports(opts),
+		},
+		WHOIS: &whois.Config{
+			Timeout:        time.Duration(opts.timeout) * time.Second,
+			FollowReferral: opts.followReferral,
+			UseRDAP:        rdapMode(opts.rdap),
+		},
+	}
+}
+
+// rdapMode turns the -rdap boolean flag into a whois.RDAPMode: enabled means
+// try RDAP first and fall back to WHOIS only when the domain has no RDAP
+// service; other RDAP errors are returned.
+func rdapMode(enabled bool) whois.RDAPMode {
+	if enabled {
+		return whois.RDAPModePrefer
+	}
+	return whois.RDAPModeOff
+}
+
+// protocolTransports turns -protocol/-doh-url/-resolver into a
+// dns.Config.Transports entry, or nil to fall back to the default
+// CustomResolver/net.Resolver behavior when -protocol is "udp" (or unset).
+func protocolTransports(opts *options) []dns.ResolverSpec {
+	switch strings.ToLower(opts.protocol) {
+	case "", "udp":
+		return nil
+	case "tcp":
+		return []dns.ResolverSpec{{URL: "tcp://" + opts.customResolver}}
+	case "dot":
+		return []dns.ResolverSpec{{URL: "tls://" + opts.customResolver}}
+	case "doh":
+		if opts.dohURL == "" {
+			fmt.Fprintln(os.Stderr, "Error: -doh-url is required when -protocol=doh")
+			os.Exit(1)
+		}
+		return []dns.ResolverSpec{{URL: opts.dohURL}}
+	case "doq":
+		return []dns.ResolverSpec{{URL: "quic://" + opts.customResolver}}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown -protocol %q (want udp, tcp, dot, doh, or doq)\n", opts.protocol)
+		os.Exit(1)
+		return nil
+	}
+}
+
 func parseFlags() *options {
 	opts := &options{}
 
@@ -111,7 +203,16 @@ func parseFlags() *options {
 	flag.IntVar(&opts.timeout, "timeout", 10, "Timeout in seconds")
 	flag.StringVar(&opts.customResolver, "resolver", "", "Custom DNS resolver (e.g., 8.8.8.8:53)")
 	flag.BoolVar(&opts.followReferral, "follow", true, "Follow WHOIS referrals")
+	flag.BoolVar(&opts.rdap, "rdap", false, "Prefer RDAP over legacy WHOIS, falling back to WHOIS when no RDAP service is registered")
 	flag.StringVar(&opts.srv, "srv", "", "SRV lookup in format: service,proto,name (e.g., xmpp,tcp,example.com)")
+	flag.StringVar(&opts.protocol, "protocol", "udp", "DNS transport: udp, tcp, dot, doh, or doq")
+	flag.StringVar(&opts.dohURL, "doh-url", "", "DoH endpoint URL (required when -protocol=doh, e.g. https://dns.google/dns-query)")
+	flag.BoolVar(&opts.dnssec, "dnssec", false, "Validate DNSSEC chain of trust and print it; exits non-zero if the answer is Bogus")
+	flag.BoolVar(&opts.trace, "trace", false, "Perform a dig +trace style iterative resolution from the root nameservers")
+	flag.StringVar(&opts.input, "input", "", "Batch mode: path to a file of domains (one per line), or '-' for stdin")
+	flag.IntVar(&opts.concurrency, "concurrency", 10, "Batch mode: number of domains looked up in parallel")
+	flag.Float64Var(&opts.ratePerServer, "rate-per-server", 0, "Batch mode: max WHOIS queries per second to a single server host (0 = unlimited)")
+	flag.BoolVar(&opts.ndjson, "ndjson", false, "Batch mode: emit newline-delimited JSON, one result per line")
 	flag.BoolVar(&opts.showVersion, "version", false, "Show version information")
 	flag.BoolVar(&opts.showVersion, "v", false, "Show version (shorthand)")
 
@@ -127,6 +228,12 @@ func parseFlags() *options {
 		fmt.Fprintf(os.Stderr, "  %s -d example.com -j                 # Output as JSON\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -srv xmpp,tcp,example.com         # SRV record lookup\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -d example.com -resolver 8.8.8.8:53  # Use custom DNS server\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -d example.com -protocol doh -doh-url https://dns.google/dns-query  # Query over DoH\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -d example.com -protocol doq -resolver dns.adguard.com:853  # Query over DoQ\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -d example.com -dnssec             # Validate DNSSEC chain of trust\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -d example.com -w -rdap            # WHOIS lookup, preferring RDAP\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -d example.com -trace              # Iterative dig +trace style resolution\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -input domains.txt -concurrency 20 -ndjson  # Batch mode\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -139,6 +246,88 @@ func parseFlags() *options {
 	return opts
 }
 
+// handleBatchLookup reads domains from -input (a file path, or "-" for
+// stdin), runs them through lookup.Client.LookupBatch, streams each result
+// as it completes, and prints a success/failure summary to stderr.
+func handleBatchLookup(opts *options) {
+	domains, err := readDomains(opts.input)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading -input: %v\n", err)
+		os.Exit(1)
+	}
+
+	client, err := lookup.NewClient(buildConfig(opts))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating client: %v\n", err)
+		os.Exit(1)
+	}
+
+	batchOpts := lookup.DefaultBatchOptions()
+	batchOpts.Concurrency = opts.concurrency
+	batchOpts.RatePerServer = opts.ratePerServer
+
+	ctx := context.Background()
+	results := client.LookupBatch(ctx, domains, batchOpts)
+
+	encoder := json.NewEncoder(os.Stdout)
+	var succeeded, failed int
+	for result := range results {
+		if result.Error != "" {
+			failed++
+		} else {
+			succeeded++
+		}
+
+		if opts.ndjson || opts.jsonOutput {
+			if err := encoder.Encode(result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error encoding result for %s: %v\n", result.Domain, err)
+			}
+			continue
+		}
+
+		if result.Error != "" {
+			fmt.Printf("%s: ERROR: %s\n", result.Domain, result.Error)
+		} else {
+			fmt.Printf("%s: ok\n", result.Domain)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "\nBatch complete: %d succeeded, %d failed, %d total\n", succeeded, failed, len(domains))
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// readDomains reads one domain per line from path, or from stdin when path
+// is "-". Blank lines and lines starting with "#" are skipped.
+func readDomains(path string) ([]string, error) {
+	var f *os.File
+	if path == "-" {
+		f = os.Stdin
+	} else {
+		var err error
+		f, err = os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer f.Close()
+	}
+
+	var domains []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domains: %w", err)
+	}
+	return domains, nil
+}
+
 func handleSRVLookup(ctx context.Context, client *lookup.Client, opts *options) {
 	parts := strings.Split(opts.srv, ",")
 	if len(parts) != 3 {
@@ -177,6 +366,50 @@ func handleDNSLookup(ctx context.Context, client *lookup.Client, opts *options)
 	}
 }
 
+func handleTrace(ctx context.Context, client *lookup.Client, opts *options) {
+	recordType := dns.RecordType(strings.ToUpper(opts.recordType))
+
+	steps, err := client.DNSTrace(ctx, opts.domain, recordType)
+	if err != nil && len(steps) == 0 {
+		fmt.Fprintf(os.Stderr, "Error performing trace: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.jsonOutput {
+		printJSON(steps)
+		return
+	}
+
+	printTraceResult(opts.domain, steps)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\nWarning: trace did not reach an authoritative answer: %v\n", err)
+	}
+}
+
+func printTraceResult(domain string, steps []dns.TraceStep) {
+	fmt.Printf("Trace for: %s\n\n", domain)
+	for i, step := range steps {
+		indent := strings.Repeat("  ", i)
+		fmt.Printf("%s%s  (queried %s, %s)\n", indent, step.Zone, step.QueriedServer, step.RTT)
+		for _, ns := range step.Nameservers {
+			fmt.Printf("%s  NS %s\n", indent, ns)
+		}
+		for _, rec := range step.Records {
+			fmt.Printf("%s  -> %s\n", indent, rrDataSummary(rec))
+		}
+	}
+}
+
+// rrDataSummary renders a ResourceRecord's rdata map as a compact key=value
+// list for the trace printer.
+func rrDataSummary(rec dns.ResourceRecord) string {
+	parts := make([]string, 0, len(rec.Data))
+	for k, v := range rec.Data {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, v))
+	}
+	return fmt.Sprintf("%s %s", rec.Name, strings.Join(parts, " "))
+}
+
 func handleWhoisLookup(ctx context.Context, client *lookup.Client, opts *options) {
 	result, err := client.WHOISLookup(ctx, opts.domain)
 	if err != nil {
@@ -0,0 +1,198 @@
+// Package cache provides the pluggable result-cache storage shared by
+// dns.Client and whois.Client. Each package keeps its own cache semantics
+// (TTL derivation, negative caching, singleflight collapsing) and uses a
+// Store purely for storage, so a Redis- or BadgerDB-backed Store can be
+// dropped in without either package needing to know about it.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Store is a pluggable key/value store with per-entry TTL. MemoryStore is
+// the built-in in-process implementation; callers that want a cache shared
+// across processes can implement Store themselves (e.g. backed by Redis or
+// BadgerDB) and set it on the owning package's CacheConfig.Store.
+type Store interface {
+	// Get returns the value stored under key, or ok=false if it's missing
+	// or expired.
+	Get(key string) (value []byte, ok bool)
+	// Set stores value under key, expiring it after ttl.
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete removes key, if present.
+	Delete(key string)
+}
+
+// Stats reports cumulative counters for a Store. Implementations that can't
+// cheaply track these (e.g. a remote Redis-backed Store) may simply not
+// implement the optional Stats() method; callers treat that the same as a
+// zero Stats value.
+type Stats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Entries   int
+}
+
+type entry struct {
+	value     []byte
+	expiresAt time.Time
+	elem      *list.Element
+}
+
+// MemoryStore is an in-process Store bounded to a maximum entry count via
+// LRU eviction, with a background janitor goroutine that sweeps expired
+// entries on a timer so they don't linger until a Get or size-based eviction
+// happens to touch them.
+type MemoryStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*entry
+	lru        *list.List // front = most recently used
+
+	hits, misses, evictions int64
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMemoryStore creates a MemoryStore holding at most maxEntries (0 means a
+// default of 10000), with a background janitor sweeping expired entries
+// every interval (0 means a default of 1 minute). Call Close to stop the
+// janitor goroutine once the store is no longer needed.
+func NewMemoryStore(maxEntries int, interval time.Duration) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = 10000
+	}
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	ms := &MemoryStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*entry),
+		lru:        list.New(),
+		stop:       make(chan struct{}),
+	}
+	go ms.runJanitor(interval)
+	return ms
+}
+
+// Get implements Store.
+func (ms *MemoryStore) Get(key string) ([]byte, bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	e, ok := ms.entries[key]
+	if !ok {
+		ms.misses++
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		ms.removeLocked(key, e)
+		ms.misses++
+		return nil, false
+	}
+
+	ms.lru.MoveToFront(e.elem)
+	ms.hits++
+	return e.value, true
+}
+
+// Set implements Store.
+func (ms *MemoryStore) Set(key string, value []byte, ttl time.Duration) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	if e, ok := ms.entries[key]; ok {
+		e.value = value
+		e.expiresAt = time.Now().Add(ttl)
+		ms.lru.MoveToFront(e.elem)
+		return
+	}
+
+	e := &entry{value: value, expiresAt: time.Now().Add(ttl)}
+	e.elem = ms.lru.PushFront(key)
+	ms.entries[key] = e
+
+	for ms.lru.Len() > ms.maxEntries {
+		ms.evictOldestLocked()
+	}
+}
+
+// Delete implements Store.
+func (ms *MemoryStore) Delete(key string) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if e, ok := ms.entries[key]; ok {
+		ms.removeLocked(key, e)
+	}
+}
+
+func (ms *MemoryStore) removeLocked(key string, e *entry) {
+	ms.lru.Remove(e.elem)
+	delete(ms.entries, key)
+}
+
+func (ms *MemoryStore) evictOldestLocked() {
+	back := ms.lru.Back()
+	if back == nil {
+		return
+	}
+	key := back.Value.(string)
+	if e, ok := ms.entries[key]; ok {
+		ms.removeLocked(key, e)
+		ms.evictions++
+	}
+}
+
+// Stats returns cumulative hit/miss/eviction counters and the current entry
+// count.
+func (ms *MemoryStore) Stats() Stats {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return Stats{
+		Hits:      ms.hits,
+		Misses:    ms.misses,
+		Evictions: ms.evictions,
+		Entries:   len(ms.entries),
+	}
+}
+
+// Close stops the background janitor goroutine. Safe to call more than
+// once; a closed MemoryStore otherwise remains usable, just without
+// eager expiry sweeps.
+func (ms *MemoryStore) Close() {
+	ms.stopOnce.Do(func() { close(ms.stop) })
+}
+
+func (ms *MemoryStore) runJanitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ms.sweep()
+		case <-ms.stop:
+			return
+		}
+	}
+}
+
+// sweep drops every entry that has already expired, so memory isn't held
+// for keys nobody will ever Get again.
+func (ms *MemoryStore) sweep() {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := time.Now()
+	for key, e := range ms.entries {
+		if now.After(e.expiresAt) {
+			ms.removeLocked(key, e)
+			ms.evictions++
+		}
+	}
+}
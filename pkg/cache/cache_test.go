@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreGetSet(t *testing.T) {
+	ms := NewMemoryStore(10, time.Hour)
+	defer ms.Close()
+
+	if _, ok := ms.Get("missing"); ok {
+		t.Error("expected miss for unset key")
+	}
+
+	ms.Set("a", []byte("1"), time.Minute)
+	v, ok := ms.Get("a")
+	if !ok || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v; want \"1\", true", v, ok)
+	}
+
+	stats := ms.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	ms := NewMemoryStore(10, time.Hour)
+	defer ms.Close()
+
+	ms.Set("a", []byte("1"), -time.Second) // already expired
+	if _, ok := ms.Get("a"); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestMemoryStoreLRUEviction(t *testing.T) {
+	ms := NewMemoryStore(2, time.Hour)
+	defer ms.Close()
+
+	ms.Set("a", []byte("1"), time.Minute)
+	ms.Set("b", []byte("2"), time.Minute)
+	ms.Get("a")                           // touch a, making b the least recently used
+	ms.Set("c", []byte("3"), time.Minute) // evicts b, not a
+
+	if _, ok := ms.Get("a"); !ok {
+		t.Error("expected a to survive eviction")
+	}
+	if _, ok := ms.Get("b"); ok {
+		t.Error("expected b to be evicted")
+	}
+	if _, ok := ms.Get("c"); !ok {
+		t.Error("expected c to be present")
+	}
+
+	if stats := ms.Stats(); stats.Evictions != 1 {
+		t.Errorf("Evictions = %d, want 1", stats.Evictions)
+	}
+}
+
+func TestMemoryStoreDelete(t *testing.T) {
+	ms := NewMemoryStore(10, time.Hour)
+	defer ms.Close()
+
+	ms.Set("a", []byte("1"), time.Minute)
+	ms.Delete("a")
+	if _, ok := ms.Get("a"); ok {
+		t.Error("expected deleted entry to miss")
+	}
+}
@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// jsonEntry is the JSON envelope a TypedCache persists into a Store, since
+// Store only deals in bytes. Err is flattened to its message, which is
+// enough for callers that just want to know whether (and why) a lookup
+// failed; a Redis-backed Store couldn't preserve Go error types across the
+// wire anyway. Named distinctly from MemoryStore's own internal entry type
+// in cache.go, which this package-level generic type would otherwise
+// clash with as a duplicate declaration.
+type jsonEntry[T any] struct {
+	Result *T
+	Err    string
+}
+
+// TypedCache adds JSON envelope handling and singleflight request collapsing
+// on top of a Store, for a single result type T. dns.Client and whois.Client
+// each have their own TTL derivation and keying rules, but otherwise cache
+// results the same way, so that plumbing lives here once instead of being
+// copied per package.
+type TypedCache[T any] struct {
+	store Store
+	sf    singleflight.Group
+}
+
+// NewTypedCache wraps store with envelope handling for T.
+func NewTypedCache[T any](store Store) *TypedCache[T] {
+	return &TypedCache[T]{store: store}
+}
+
+// Get returns the cached value for key, if present and unexpired. Like
+// json.Unmarshal in general, a *T field that was an empty (non-nil) map or
+// slice at Put time comes back nil here if it was dropped by an
+// `omitempty` tag; callers that depend on such a field being non-nil after
+// a hit must re-initialize it themselves.
+func (tc *TypedCache[T]) Get(key string) (result *T, err error, ok bool) {
+	raw, found := tc.store.Get(key)
+	if !found {
+		return nil, nil, false
+	}
+
+	var e jsonEntry[T]
+	if unmarshalErr := json.Unmarshal(raw, &e); unmarshalErr != nil {
+		return nil, nil, false
+	}
+
+	if e.Err != "" {
+		err = errors.New(e.Err)
+	}
+	return e.Result, err, true
+}
+
+// Do calls fetch, collapsing concurrent calls for the same key via
+// singleflight so a thundering herd only does the work once.
+func (tc *TypedCache[T]) Do(key string, fetch func() (*T, error)) (*T, error) {
+	v, err, _ := tc.sf.Do(key, func() (interface{}, error) {
+		return fetch()
+	})
+	if v == nil {
+		return nil, err
+	}
+	return v.(*T), err
+}
+
+// Put stores result/err under key with the given ttl.
+func (tc *TypedCache[T]) Put(key string, result *T, err error, ttl time.Duration) {
+	e := jsonEntry[T]{Result: result}
+	if err != nil {
+		e.Err = err.Error()
+	}
+	raw, marshalErr := json.Marshal(e)
+	if marshalErr != nil {
+		return
+	}
+	tc.store.Set(key, raw, ttl)
+}
@@ -0,0 +1,289 @@
+// Package discovery implements a small DNS-based service discovery layer on
+// top of pkg/dns, inspired by Prometheus' dns_sd discovery mechanism: a set
+// of names (or SRV services) is polled on an interval derived from the
+// answer's TTL, and added/removed/modified targets are published to
+// subscribers as they're observed.
+package discovery
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/dns-lookup/pkg/dns"
+)
+
+// Query describes one name to keep resolved. Set Service/Proto to resolve
+// it as an SRV lookup (e.g. Service="http", Proto="tcp", Name="example.com"
+// resolves _http._tcp.example.com);
+// leave them empty to resolve Type (A by default).
+type Query struct {
+	Name    string
+	Type    dns.RecordType
+	Service string
+	Proto   string
+}
+
+// key identifies a Query for bookkeeping (subscriber fan-out, previous
+// result sets).
+func (q Query) key() string {
+	if q.Service != "" {
+		return q.Service + "." + q.Proto + "." + q.Name
+	}
+	return string(q.Type) + "|" + q.Name
+}
+
+// Target is a single resolved endpoint.
+type Target struct {
+	Host     string
+	Port     int
+	Priority uint16
+	Weight   uint16
+	Labels   map[string]string
+}
+
+func (t Target) key() string {
+	if t.Port == 0 {
+		return t.Host
+	}
+	return t.Host + ":" + strconv.Itoa(t.Port)
+}
+
+// equalExceptLabels reports whether t and other have the same
+// host/port/priority/weight, ignoring Labels (which only ever carries the
+// originating query and so never meaningfully differs between refreshes).
+func (t Target) equalExceptLabels(other Target) bool {
+	return t.Host == other.Host && t.Port == other.Port &&
+		t.Priority == other.Priority && t.Weight == other.Weight
+}
+
+// EventType distinguishes the three kinds of change a refresh can observe.
+type EventType string
+
+const (
+	// Added is emitted the first time a target is observed.
+	Added EventType = "added"
+	// Removed is emitted when a previously observed target disappears.
+	Removed EventType = "removed"
+	// Modified is emitted when a target's priority/weight change between
+	// refreshes; Labels are not compared.
+	Modified EventType = "modified"
+)
+
+// Event is published to subscribers whenever a refresh's result set differs
+// from the previous one for a Query.
+type Event struct {
+	Type   EventType
+	Query  Query
+	Target Target
+}
+
+// Config configures a Discoverer.
+type Config struct {
+	Names []Query
+	// RefreshInterval is used when the answer's TTL is zero or the
+	// underlying resolver doesn't expose TTLs (e.g. a plain net.Resolver
+	// backend via dns.Client rather than the miekg wire-protocol path).
+	RefreshInterval time.Duration
+	// Jitter is added/subtracted randomly to each refresh's wait to avoid
+	// every query waking up in lockstep.
+	Jitter time.Duration
+	// Metrics, if set, receives lookups_total / lookup_failures_total /
+	// lookup_duration_seconds observations. Each metric is requested by name
+	// only, so every query feeds the same Counter/Histogram. Defaults to a
+	// no-op implementation.
+	Metrics Registerer
+}
+
+// Discoverer periodically resolves a set of Query values and publishes
+// Added/Removed/Modified events to subscribers.
+type Discoverer struct {
+	client  *dns.Client
+	cfg     Config
+	metrics Registerer
+
+	mu          sync.Mutex
+	subscribers []chan Event
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a Discoverer over client using cfg. Call Subscribe before Run
+// to avoid missing early events.
+func New(client *dns.Client, cfg Config) *Discoverer {
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = 30 * time.Second
+	}
+	metrics := cfg.Metrics
+	if metrics == nil {
+		metrics = noopRegisterer{}
+	}
+	return &Discoverer{
+		client:  client,
+		cfg:     cfg,
+		metrics: metrics,
+		done:    make(chan struct{}),
+	}
+}
+
+// Subscribe returns a channel of Events. The channel is buffered; slow
+// subscribers that fall behind will not block the discoverer, but may miss
+// events once the buffer fills.
+func (d *Discoverer) Subscribe() <-chan Event {
+	ch := make(chan Event, 64)
+	d.mu.Lock()
+	d.subscribers = append(d.subscribers, ch)
+	d.mu.Unlock()
+	return ch
+}
+
+func (d *Discoverer) publish(ev Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, ch := range d.subscribers {
+		select {
+		case ch <- ev:
+		default:
+			// Drop rather than block; a slow subscriber shouldn't stall
+			// discovery for everyone else.
+		}
+	}
+}
+
+// Run starts one goroutine per configured Query and blocks until ctx is
+// canceled or Stop is called.
+func (d *Discoverer) Run(ctx context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+
+	var wg sync.WaitGroup
+	for _, q := range d.cfg.Names {
+		q := q
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.runQuery(ctx, q)
+		}()
+	}
+
+	wg.Wait()
+	close(d.done)
+}
+
+// Stop cancels all running queries. Run returns once every query goroutine
+// has exited.
+func (d *Discoverer) Stop() {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	<-d.done
+}
+
+// runQuery repeatedly resolves q, diffs the result against the previous
+// refresh, and publishes events for whatever changed, sleeping between
+// refreshes for the answer's TTL (or RefreshInterval, jittered, as a
+// fallback).
+func (d *Discoverer) runQuery(ctx context.Context, q Query) {
+	lookupsTotal := d.metrics.Counter(metricLookupsTotal)
+	lookupFailures := d.metrics.Counter(metricLookupFailuresTotal)
+	lookupDuration := d.metrics.Histogram(metricLookupDurationSecond)
+
+	previous := make(map[string]Target)
+
+	for {
+		start := time.Now()
+		targets, ttl, err := d.resolve(ctx, q)
+		lookupsTotal.Inc()
+		lookupDuration.Observe(time.Since(start).Seconds())
+		if err != nil {
+			lookupFailures.Inc()
+		} else {
+			d.diffAndPublish(q, previous, targets)
+			previous = targets
+		}
+
+		wait := d.cfg.RefreshInterval
+		if ttl > 0 {
+			wait = ttl
+		}
+		wait = applyJitter(wait, d.cfg.Jitter)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// resolve performs a single lookup for q, returning the target set keyed by
+// Target.key() and the TTL observed on the answer, if any.
+func (d *Discoverer) resolve(ctx context.Context, q Query) (map[string]Target, time.Duration, error) {
+	if q.Service != "" {
+		result, err := d.client.LookupSRV(ctx, q.Service, q.Proto, q.Name)
+		if err != nil {
+			return nil, 0, err
+		}
+		targets := make(map[string]Target, len(result.SRVRecords))
+		for _, srv := range result.SRVRecords {
+			t := Target{
+				Host:     srv.Target,
+				Port:     int(srv.Port),
+				Priority: srv.Priority,
+				Weight:   srv.Weight,
+				Labels:   map[string]string{"query": q.key()},
+			}
+			targets[t.key()] = t
+		}
+		return targets, result.TTL, nil
+	}
+
+	recordType := q.Type
+	if recordType == "" {
+		recordType = dns.RecordTypeA
+	}
+	result, err := d.client.Lookup(ctx, q.Name, recordType)
+	if err != nil {
+		return nil, 0, err
+	}
+	targets := make(map[string]Target, len(result.Records))
+	for _, host := range result.Records {
+		t := Target{Host: host, Labels: map[string]string{"query": q.key()}}
+		targets[t.key()] = t
+	}
+	return targets, result.TTL, nil
+}
+
+// diffAndPublish compares previous against current and emits
+// Added/Removed/Modified events for whatever changed.
+func (d *Discoverer) diffAndPublish(q Query, previous, current map[string]Target) {
+	for k, t := range current {
+		if old, ok := previous[k]; !ok {
+			d.publish(Event{Type: Added, Query: q, Target: t})
+		} else if !old.equalExceptLabels(t) {
+			d.publish(Event{Type: Modified, Query: q, Target: t})
+		}
+	}
+	for k, t := range previous {
+		if _, ok := current[k]; !ok {
+			d.publish(Event{Type: Removed, Query: q, Target: t})
+		}
+	}
+}
+
+// applyJitter adds a random offset in [-jitter, +jitter] to base; if the
+// result would be negative, base is returned unchanged.
+func applyJitter(base, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return base
+	}
+	offset := time.Duration(rand.Int63n(int64(jitter)*2)) - jitter
+	d := base + offset
+	if d < 0 {
+		return base
+	}
+	return d
+}
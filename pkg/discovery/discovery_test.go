@@ -0,0 +1,42 @@
+package discovery
+
+import (
+	"testing"
+)
+
+func TestDiffAndPublish(t *testing.T) {
+	d := New(nil, Config{})
+	events := d.Subscribe()
+
+	q := Query{Name: "example.com"}
+	previous := map[string]Target{
+		"1.2.3.4": {Host: "1.2.3.4"},
+		"5.6.7.8": {Host: "5.6.7.8"},
+	}
+	current := map[string]Target{
+		"1.2.3.4": {Host: "1.2.3.4", Priority: 1}, // modified
+		"9.9.9.9": {Host: "9.9.9.9"},              // added
+		// 5.6.7.8 removed
+	}
+
+	d.diffAndPublish(q, previous, current)
+	close(d.subscribers[0])
+
+	got := map[EventType]int{}
+	for ev := range events {
+		got[ev.Type]++
+	}
+
+	if got[Added] != 1 || got[Removed] != 1 || got[Modified] != 1 {
+		t.Errorf("unexpected event counts: %+v", got)
+	}
+}
+
+func TestTargetKey(t *testing.T) {
+	if (Target{Host: "example.com"}).key() != "example.com" {
+		t.Error("expected bare host key when Port is zero")
+	}
+	if (Target{Host: "example.com", Port: 8080}).key() != "example.com:8080" {
+		t.Error("expected host:port key when Port is set")
+	}
+}
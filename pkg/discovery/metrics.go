@@ -0,0 +1,50 @@
+package discovery
+
+// This package deliberately does not depend on client_golang so that users
+// who don't want a Prometheus dependency pulled in can still use it. Anyone
+// wiring metrics into an existing Prometheus registry implements Registerer
+// with thin adapters around prometheus.Counter/Histogram.
+
+// Counter is a monotonically increasing value, e.g. total lookups.
+type Counter interface {
+	Inc()
+}
+
+// Histogram observes a distribution of values, e.g. lookup durations in
+// seconds.
+type Histogram interface {
+	Observe(value float64)
+}
+
+// Registerer is the minimal surface Discoverer needs to publish metrics
+// without importing a specific metrics library. Implementations typically
+// wrap prometheus.Counter/Histogram; Discoverer asks for each metric by name
+// only, with no per-query label.
+type Registerer interface {
+	// Counter returns (creating if necessary) the named counter.
+	Counter(name string) Counter
+	// Histogram returns (creating if necessary) the named histogram.
+	Histogram(name string) Histogram
+}
+
+// noopCounter/noopHistogram back the zero-value Registerer so Discoverer
+// can record metrics unconditionally without nil checks scattered through
+// its run loop.
+type noopRegisterer struct{}
+
+func (noopRegisterer) Counter(string) Counter     { return noopCounter{} }
+func (noopRegisterer) Histogram(string) Histogram { return noopHistogram{} }
+
+type noopCounter struct{}
+
+func (noopCounter) Inc() {}
+
+type noopHistogram struct{}
+
+func (noopHistogram) Observe(float64) {}
+
+const (
+	metricLookupsTotal         = "lookups_total"
+	metricLookupFailuresTotal  = "lookup_failures_total"
+	metricLookupDurationSecond = "lookup_duration_seconds"
+)
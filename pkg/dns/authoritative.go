@@ -0,0 +1,150 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+// LookupAuthoritative finds the zone authoritative for domain by walking up
+// from the full name (sub.example.com -> example.com -> com) until an NS
+// lookup succeeds, then queries each of that zone's nameservers directly
+// (bypassing the recursive resolver) for (domain, recordType). The result
+// maps each queried nameserver to its own answer, and Consistent reports
+// whether every nameserver that answered returned the same record set, so
+// callers can detect DNS misconfiguration or desync between authoritative
+// servers.
+func (c *Client) LookupAuthoritative(ctx context.Context, domain string, recordType RecordType) (map[string]*LookupResult, bool, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+	}
+
+	_, nameservers, err := c.findAuthoritativeZone(ctx, domain)
+	if err != nil {
+		return nil, false, err
+	}
+
+	results := make(map[string]*LookupResult, len(nameservers))
+	for _, ns := range nameservers {
+		results[ns] = c.queryNameserverDirect(ctx, ns, domain, recordType)
+	}
+
+	return results, recordSetsConsistent(results), nil
+}
+
+// findAuthoritativeZone walks up domain's labels, doing an NS lookup at each
+// one, and returns the first (longest) zone that has NS records along with
+// those nameservers.
+func (c *Client) findAuthoritativeZone(ctx context.Context, domain string) (string, []string, error) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(domain, ".")), ".")
+
+	for start := 0; start < len(labels); start++ {
+		zone := strings.Join(labels[start:], ".")
+		result, err := c.Lookup(ctx, zone, RecordTypeNS)
+		if err == nil && len(result.NameServers) > 0 {
+			return zone, result.NameServers, nil
+		}
+	}
+
+	return "", nil, fmt.Errorf("no NS records found for %q or any parent zone", domain)
+}
+
+// queryNameserverDirect queries ns non-recursively for (domain, recordType),
+// bypassing the recursive resolver entirely. Errors are recorded on the
+// returned LookupResult rather than surfaced, so a single unreachable
+// nameserver doesn't stop LookupAuthoritative from reporting the others.
+func (c *Client) queryNameserverDirect(ctx context.Context, ns, domain string, recordType RecordType) *LookupResult {
+	result := &LookupResult{
+		Domain:     domain,
+		RecordType: recordType,
+		Timestamp:  time.Now(),
+		Metadata:   map[string]interface{}{"nameserver": ns},
+	}
+
+	qtype, ok := recordTypeToQtype[recordType]
+	if !ok {
+		result.Error = fmt.Sprintf("unsupported record type: %s", recordType)
+		return result
+	}
+
+	raw, err := newRawResolver(withPort53(ns), c.timeout)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	msg := new(miekgdns.Msg)
+	msg.SetQuestion(miekgdns.Fqdn(domain), qtype)
+	msg.RecursionDesired = false
+
+	reply, _, err := raw.exchange(ctx, msg)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	if reply.Rcode != miekgdns.RcodeSuccess {
+		result.Error = fmt.Sprintf("dns query failed with rcode %s", miekgdns.RcodeToString[reply.Rcode])
+		return result
+	}
+
+	result.Raw = rrsToResourceRecords(reply.Answer, recordType)
+	for _, rr := range reply.Answer {
+		result.Records = append(result.Records, rrDataString(rr))
+	}
+
+	var minTTL time.Duration
+	for i, rec := range result.Raw {
+		if i == 0 || rec.TTL < minTTL {
+			minTTL = rec.TTL
+		}
+	}
+	result.TTL = minTTL
+
+	return result
+}
+
+// recordSetsConsistent reports whether every nameserver in results that
+// answered without error returned the same set of records. It's false if
+// every nameserver errored, since nothing was actually confirmed.
+func recordSetsConsistent(results map[string]*LookupResult) bool {
+	var reference []string
+	seenAny := false
+
+	for _, result := range results {
+		if result.Error != "" {
+			continue
+		}
+		sorted := append([]string(nil), result.Records...)
+		sort.Strings(sorted)
+
+		if !seenAny {
+			reference = sorted
+			seenAny = true
+			continue
+		}
+		if !equalRecordSets(reference, sorted) {
+			return false
+		}
+	}
+
+	return seenAny
+}
+
+func equalRecordSets(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+package dns
+
+import "testing"
+
+func TestRecordSetsConsistent(t *testing.T) {
+	tests := []struct {
+		name    string
+		results map[string]*LookupResult
+		want    bool
+	}{
+		{
+			name: "all agree",
+			results: map[string]*LookupResult{
+				"ns1.example.com": {Records: []string{"192.0.2.1", "192.0.2.2"}},
+				"ns2.example.com": {Records: []string{"192.0.2.2", "192.0.2.1"}},
+			},
+			want: true,
+		},
+		{
+			name: "disagreement",
+			results: map[string]*LookupResult{
+				"ns1.example.com": {Records: []string{"192.0.2.1"}},
+				"ns2.example.com": {Records: []string{"192.0.2.2"}},
+			},
+			want: false,
+		},
+		{
+			name: "errored nameserver ignored",
+			results: map[string]*LookupResult{
+				"ns1.example.com": {Records: []string{"192.0.2.1"}},
+				"ns2.example.com": {Error: "timeout"},
+			},
+			want: true,
+		},
+		{
+			name: "every nameserver errored",
+			results: map[string]*LookupResult{
+				"ns1.example.com": {Error: "timeout"},
+			},
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := recordSetsConsistent(tt.results); got != tt.want {
+				t.Errorf("recordSetsConsistent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package dns
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kataras/dns-lookup/pkg/cache"
+)
+
+// CacheConfig controls the optional in-process result cache on Client.
+type CacheConfig struct {
+	// Enabled turns the cache on. Disabled by default for backward
+	// compatibility with callers that expect every Lookup to hit the wire.
+	Enabled bool
+	// MaxEntries bounds the number of cached (qname, qtype, class) keys when
+	// the default MemoryStore is used. Oldest (LRU) entries are evicted once
+	// the limit is reached. Zero means a reasonable default (10000). Has no
+	// effect if Store is set.
+	MaxEntries int
+	// NegativeTTL is how long NXDOMAIN/NODATA responses are cached for when
+	// no SOA MINIMUM is available to derive a TTL from (see NegativeError).
+	// Only non-success rcodes (e.g. NXDOMAIN) from the wire-protocol resolver
+	// or Transports carry a SOA MINIMUM; NODATA answers (success with an
+	// empty answer section) and answers served over net.Resolver always use
+	// NegativeTTL.
+	NegativeTTL time.Duration
+	// MinTTL/MaxTTL clamp the TTL used for a cache entry, guarding against
+	// misconfigured authoritative servers advertising a TTL of zero or an
+	// unreasonably large one.
+	MinTTL time.Duration
+	MaxTTL time.Duration
+	// Disable mirrors Xray's disableCache escape hatch: when true the cache
+	// is bypassed even if Enabled is also true, without having to unset the
+	// rest of the config.
+	Disable bool
+	// Store overrides the storage backing the cache, e.g. with a Redis- or
+	// BadgerDB-backed cache.Store for a cache shared across processes. Left
+	// nil, an in-process cache.MemoryStore bounded by MaxEntries is used.
+	Store cache.Store
+}
+
+// CacheStats reports cumulative counters for Client's result cache.
+type CacheStats = cache.Stats
+
+type cacheKey struct {
+	qname string
+	qtype RecordType
+	class string
+}
+
+// String renders k as the flat string key the underlying cache.Store sees.
+func (k cacheKey) String() string {
+	return fmt.Sprintf("%s|%s|%s", k.qname, k.qtype, k.class)
+}
+
+// resultCache is a TTL-aware cache with negative caching and singleflight
+// request collapsing, keyed by (qname, qtype, class). It delegates envelope
+// handling and storage to a cache.TypedCache, so the JSON/singleflight
+// plumbing isn't duplicated per package (see pkg/whois's resultCache for the
+// other instantiation).
+type resultCache struct {
+	store cache.Store
+	typed *cache.TypedCache[LookupResult]
+	cfg   CacheConfig
+
+	mu           sync.Mutex
+	keysByDomain map[string]map[cacheKey]struct{}
+}
+
+func newResultCache(cfg CacheConfig) *resultCache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = 30 * time.Second
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = cache.NewMemoryStore(cfg.MaxEntries, time.Minute)
+	}
+
+	return &resultCache{
+		store:        store,
+		typed:        cache.NewTypedCache[LookupResult](store),
+		cfg:          cfg,
+		keysByDomain: make(map[string]map[cacheKey]struct{}),
+	}
+}
+
+// clampTTL enforces cfg.MinTTL/MaxTTL on an observed record TTL.
+func (rc *resultCache) clampTTL(ttl time.Duration) time.Duration {
+	if rc.cfg.MinTTL > 0 && ttl < rc.cfg.MinTTL {
+		ttl = rc.cfg.MinTTL
+	}
+	if rc.cfg.MaxTTL > 0 && ttl > rc.cfg.MaxTTL {
+		ttl = rc.cfg.MaxTTL
+	}
+	return ttl
+}
+
+func key(qname string, qtype RecordType) cacheKey {
+	return cacheKey{qname: qname, qtype: qtype, class: "IN"}
+}
+
+// lookup serves key from cache if present and unexpired, otherwise calls
+// fetch (collapsed via singleflight across concurrent callers for the same
+// key) and stores the outcome. hit reports whether the cache served the
+// answer without calling fetch.
+//
+// A cache hit's Result comes back from a JSON round-trip, so any field that
+// was an empty (non-nil) map at put time - e.g. Metadata, tagged
+// `omitempty` - comes back nil rather than empty; ensureMetadata restores
+// the non-nil invariant callers rely on before they write into it.
+func (rc *resultCache) lookup(k cacheKey, fetch func() (*LookupResult, error)) (result *LookupResult, err error, hit bool) {
+	if rc.cfg.Disable || !rc.cfg.Enabled {
+		result, err = fetch()
+		return result, err, false
+	}
+
+	if result, err, ok := rc.typed.Get(k.String()); ok {
+		ensureMetadata(result)
+		return result, err, true
+	}
+
+	result, err = rc.typed.Do(k.String(), func() (*LookupResult, error) {
+		res, ferr := fetch()
+		rc.put(k, res, ferr)
+		return res, ferr
+	})
+	return result, err, false
+}
+
+// ensureMetadata makes result.Metadata non-nil if result is non-nil, so
+// callers can unconditionally write into it after a cache hit.
+func ensureMetadata(result *LookupResult) {
+	if result != nil && result.Metadata == nil {
+		result.Metadata = make(map[string]interface{})
+	}
+}
+
+// put derives this entry's TTL (the record's own TTL for a positive answer,
+// the RFC 2308 SOA MINIMUM for a negative one when err is a NegativeError
+// carrying it, otherwise cfg.NegativeTTL) and stores it via rc.store.
+func (rc *resultCache) put(k cacheKey, result *LookupResult, err error) {
+	negative := err != nil || result == nil || len(result.Records) == 0
+
+	var ttl time.Duration
+	switch {
+	case negative:
+		ttl = rc.cfg.NegativeTTL
+		var negErr *NegativeError
+		if errors.As(err, &negErr) && negErr.SOAMinTTL > 0 {
+			ttl = negErr.SOAMinTTL
+		}
+	default:
+		ttl = rc.clampTTL(result.TTL)
+		if ttl <= 0 {
+			ttl = rc.clampTTL(5 * time.Minute)
+		}
+	}
+
+	rc.typed.Put(k.String(), result, err, ttl)
+
+	rc.mu.Lock()
+	if rc.keysByDomain[k.qname] == nil {
+		rc.keysByDomain[k.qname] = make(map[cacheKey]struct{})
+	}
+	rc.keysByDomain[k.qname][k] = struct{}{}
+	rc.mu.Unlock()
+}
+
+// purge drops every cached entry for domain, across all record types.
+func (rc *resultCache) purge(domain string) {
+	rc.mu.Lock()
+	keys := rc.keysByDomain[domain]
+	delete(rc.keysByDomain, domain)
+	rc.mu.Unlock()
+
+	for k := range keys {
+		rc.store.Delete(k.String())
+	}
+}
+
+// statsStore is implemented by cache.Store backends that can cheaply report
+// Stats; cache.MemoryStore does, a remote store might not.
+type statsStore interface {
+	Stats() cache.Stats
+}
+
+func (rc *resultCache) stats() CacheStats {
+	if ss, ok := rc.store.(statsStore); ok {
+		return ss.Stats()
+	}
+	return CacheStats{}
+}
+
+// PurgeCache removes every cached entry for domain. It is a no-op if
+// caching is disabled.
+func (c *Client) PurgeCache(domain string) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.purge(domain)
+}
+
+// CacheStats returns cumulative hit/miss/eviction counters for the result
+// cache. Returns a zero value if caching is disabled.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
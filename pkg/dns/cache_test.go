@@ -0,0 +1,117 @@
+package dns
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kataras/dns-lookup/pkg/cache"
+)
+
+func TestResultCacheHitMiss(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true, MinTTL: time.Minute})
+
+	calls := 0
+	fetch := func() (*LookupResult, error) {
+		calls++
+		return &LookupResult{Domain: "example.com", Records: []string{"1.2.3.4"}, TTL: time.Minute}, nil
+	}
+
+	k := key("example.com", RecordTypeA)
+
+	if _, _, hit := rc.lookup(k, fetch); hit {
+		t.Error("expected first lookup to miss")
+	}
+	if _, _, hit := rc.lookup(k, fetch); !hit {
+		t.Error("expected second lookup to hit")
+	}
+	if calls != 1 {
+		t.Errorf("expected fetch to run once, ran %d times", calls)
+	}
+}
+
+func TestResultCacheNegativeCaching(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true, NegativeTTL: time.Minute})
+
+	calls := 0
+	fetch := func() (*LookupResult, error) {
+		calls++
+		return &LookupResult{Domain: "nxdomain.example"}, errors.New("NXDOMAIN")
+	}
+
+	k := key("nxdomain.example", RecordTypeA)
+	rc.lookup(k, fetch)
+	_, _, hit := rc.lookup(k, fetch)
+	if !hit {
+		t.Error("expected negative result to be cached")
+	}
+	if calls != 1 {
+		t.Errorf("expected fetch to run once, ran %d times", calls)
+	}
+}
+
+// ttlRecordingStore wraps a cache.Store and remembers the ttl of the most
+// recent Set, since MemoryStore doesn't expose an entry's TTL.
+type ttlRecordingStore struct {
+	cache.Store
+	lastTTL time.Duration
+}
+
+func (s *ttlRecordingStore) Set(key string, value []byte, ttl time.Duration) {
+	s.lastTTL = ttl
+	s.Store.Set(key, value, ttl)
+}
+
+func TestResultCacheHonorsSOAMinTTL(t *testing.T) {
+	inner := cache.NewMemoryStore(10, time.Hour)
+	defer inner.Close()
+	store := &ttlRecordingStore{Store: inner}
+	rc := newResultCache(CacheConfig{Enabled: true, NegativeTTL: time.Hour, Store: store})
+
+	k := key("nxdomain.example", RecordTypeA)
+	negErr := &NegativeError{Err: errors.New("dns query failed with rcode NXDOMAIN"), SOAMinTTL: 5 * time.Second}
+	rc.put(k, &LookupResult{Domain: "nxdomain.example"}, negErr)
+
+	raw, ok := rc.store.Get(k.String())
+	if !ok {
+		t.Fatal("expected entry to be stored")
+	}
+	if len(raw) == 0 {
+		t.Error("expected non-empty cached value")
+	}
+
+	// The entry must use the SOA MINIMUM (5s), not the much longer
+	// NegativeTTL (1h).
+	if store.lastTTL != 5*time.Second {
+		t.Errorf("stored TTL = %s, want 5s (SOA MINIMUM)", store.lastTTL)
+	}
+}
+
+func TestResultCachePluggableStore(t *testing.T) {
+	store := cache.NewMemoryStore(10, time.Hour)
+	defer store.Close()
+
+	rc := newResultCache(CacheConfig{Enabled: true, Store: store})
+	k := key("example.com", RecordTypeA)
+	rc.put(k, &LookupResult{Domain: "example.com", Records: []string{"1.2.3.4"}, TTL: time.Minute}, nil)
+
+	if _, ok := store.Get(k.String()); !ok {
+		t.Error("expected the custom Store to receive the cached entry")
+	}
+
+	if stats := rc.stats(); stats.Entries != 1 {
+		t.Errorf("stats().Entries = %d, want 1", stats.Entries)
+	}
+}
+
+func TestResultCachePurge(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true})
+	k := key("example.com", RecordTypeA)
+	rc.put(k, &LookupResult{Domain: "example.com", Records: []string{"1.2.3.4"}, TTL: time.Minute}, nil)
+
+	rc.purge("example.com")
+
+	if _, _, hit := rc.lookup(k, func() (*LookupResult, error) { return nil, nil }); hit {
+		t.Error("expected purged entry to miss")
+	}
+}
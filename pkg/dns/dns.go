@@ -29,8 +29,46 @@ const (
 	RecordTypePTR RecordType = "PTR"
 	// RecordTypeSRV represents service records.
 	RecordTypeSRV RecordType = "SRV"
+	// RecordTypeCAA represents certification authority authorization records.
+	RecordTypeCAA RecordType = "CAA"
+	// RecordTypeDS represents delegation signer records used to build a DNSSEC chain of trust.
+	RecordTypeDS RecordType = "DS"
+	// RecordTypeDNSKEY represents DNSSEC public key records.
+	RecordTypeDNSKEY RecordType = "DNSKEY"
+	// RecordTypeNAPTR represents naming authority pointer records.
+	RecordTypeNAPTR RecordType = "NAPTR"
+	// RecordTypeTLSA represents TLSA certificate association records used by DANE.
+	RecordTypeTLSA RecordType = "TLSA"
+	// RecordTypeHINFO represents host information records.
+	RecordTypeHINFO RecordType = "HINFO"
 )
 
+// rawRecordTypes are record types that net.Resolver has no equivalent for
+// (TTLs, DNSSEC data, rdata beyond what the stdlib exposes). Lookups for
+// these always go through the wire-protocol resolver in raw.go regardless
+// of Config.Protocol.
+var rawRecordTypes = map[RecordType]bool{
+	RecordTypeSOA:    true,
+	RecordTypeCAA:    true,
+	RecordTypeDS:     true,
+	RecordTypeDNSKEY: true,
+	RecordTypeNAPTR:  true,
+	RecordTypeTLSA:   true,
+	RecordTypeHINFO:  true,
+}
+
+// ResourceRecord is a typed view of a single answer resource record obtained
+// from the wire-protocol resolver. It exists alongside LookupResult.Records
+// (the flattened string list) so callers can reach rdata that doesn't fit
+// the string-list model, such as CAA flags/tag/value or DNSKEY key material.
+type ResourceRecord struct {
+	Name  string            `json:"name"`
+	Type  RecordType        `json:"type"`
+	Class string            `json:"class"`
+	TTL   time.Duration     `json:"ttl"`
+	Data  map[string]string `json:"data"`
+}
+
 // LookupResult contains the results of a DNS lookup, including the queried domain,
 // record type, resolved records, and metadata about the query.
 type LookupResult struct {
@@ -41,16 +79,41 @@ type LookupResult struct {
 	SRVRecords  []*net.SRV             `json:"srv_records,omitempty"`
 	NameServers []string               `json:"name_servers,omitempty"`
 	TTL         time.Duration          `json:"ttl,omitempty"`
+	Raw         []ResourceRecord       `json:"raw,omitempty"`
 	Timestamp   time.Time              `json:"timestamp"`
 	Error       string                 `json:"error,omitempty"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
+	// AuthenticationStatus and ChainTrace are populated when Config.Validate
+	// is set (or Client.Validate is called directly): Secure means the
+	// chain of trust verified down from the root, Insecure means the zone
+	// isn't signed, Bogus means a signature or DS digest failed to verify,
+	// and Indeterminate means a fetch needed along the way failed.
+	AuthenticationStatus AuthenticationStatus `json:"authentication_status,omitempty"`
+	ChainTrace           []string             `json:"chain_trace,omitempty"`
+	// DNSSEC is populated when Config.DNSSEC is set: it reports the
+	// resolver's own AD bit rather than a locally verified chain of trust.
+	// See AuthenticationStatus/ChainTrace (Config.Validate) for full local
+	// verification.
+	DNSSEC *DNSSECResult `json:"dnssec,omitempty"`
 }
 
 // Client is a DNS lookup client that performs DNS queries with configurable
 // timeout and custom resolver support.
 type Client struct {
-	resolver *net.Resolver
-	timeout  time.Duration
+	netResolver *net.Resolver
+	timeout     time.Duration
+	raw         *rawResolver
+	protocol    string
+	multi       *multiTransportResolver
+	cache       *resultCache
+	strategy    QueryStrategy
+	parallelism int
+	resolver    Resolver
+	validate    bool
+	dnssec      bool
+	ednsDO      bool
+	ednsCD      bool
+	disabled    map[RecordType]bool
 }
 
 // Config contains configuration options for the DNS client.
@@ -59,8 +122,78 @@ type Config struct {
 	Timeout time.Duration
 	// CustomResolver allows specifying a custom DNS server (e.g., "8.8.8.8:53").
 	CustomResolver string
+	// Protocol selects the resolver backend: "stdlib" (default) uses
+	// net.Resolver for the record types it supports; "miekg" forces every
+	// lookup through the wire-protocol resolver in raw.go so TTLs and the
+	// Raw resource records are always populated. Record types net.Resolver
+	// has no concept of (SOA, CAA, DS, DNSKEY, NAPTR, TLSA, HINFO) always
+	// use the wire-protocol resolver regardless of this setting.
+	Protocol string
+	// Transports configures one or more upstream nameservers by URL,
+	// allowing encrypted transports (DoT/DoH/DoQ) alongside plain udp/tcp.
+	// When set, it takes over from CustomResolver/Protocol for every
+	// lookup.
+	Transports []ResolverSpec
+	// Strategy controls how Transports are used when more than one is
+	// configured (default: sequential fallback).
+	Strategy Strategy
+	// Cache enables the optional TTL-aware result cache. Disabled by
+	// default.
+	Cache CacheConfig
+	// QueryStrategy controls which address families LookupAll and
+	// LookupHost query (default: UseIP, i.e. both A and AAAA).
+	QueryStrategy QueryStrategy
+	// Parallelism bounds how many concurrent upstream queries LookupAll and
+	// LookupHost may issue at once (default: len of the record types/families
+	// being queried, i.e. unbounded fan-out).
+	Parallelism int
+	// Resolver overrides how lookups are actually performed, e.g. with a
+	// FakeResolver in tests or a HostsResolver to answer without touching
+	// the network. When set, it takes priority over CustomResolver,
+	// Protocol, and Transports.
+	Resolver Resolver
+	// Validate enables DNSSEC chain-of-trust validation on every Lookup,
+	// populating LookupResult.AuthenticationStatus and ChainTrace. Requires
+	// the wire-protocol resolver (it sets the EDNS0 DO bit and needs
+	// RRSIG/DNSKEY/DS, none of which net.Resolver can return), so it has no
+	// effect when Config.Resolver overrides the resolver entirely.
+	Validate bool
+	// DNSSEC enables a lighter-weight alternative to Validate: instead of
+	// chasing the chain of trust locally, it issues one query with the
+	// EDNS0 DO bit and reports the resolver's own AD (authenticated data)
+	// flag in LookupResult.DNSSEC. Like Validate, it requires the
+	// wire-protocol resolver and has no effect under Config.Resolver.
+	DNSSEC bool
+	// EDNS0DO sets the EDNS0 "DNSSEC OK" bit on queries made by Validate and
+	// DNSSEC, requesting RRSIG/DNSKEY/NSEC alongside the answer. It's forced
+	// on whenever Validate or DNSSEC is set, since both require it. Plain
+	// lookups never carry the EDNS0 OPT record, whatever this is set to.
+	EDNS0DO bool
+	// EDNS0CD sets the checking-disabled bit on the queries made by Validate
+	// and DNSSEC, asking upstream resolvers to skip their own validation and
+	// return the answer regardless of its DNSSEC status. Off by default.
+	EDNS0CD bool
+	// DisabledRecordTypes suppresses specific record types from LookupAll
+	// entirely, beyond what QueryStrategy already filters for A/AAAA. Useful
+	// for skipping types a caller doesn't care about without issuing the
+	// query at all.
+	DisabledRecordTypes []RecordType
 }
 
+// QueryStrategy mirrors the UseIP/UseIPv4/UseIPv6 knob used by other
+// resolver stacks (e.g. Xray-core) to control which address families are
+// queried.
+type QueryStrategy string
+
+const (
+	// QueryStrategyUseIP queries both A and AAAA. This is the default.
+	QueryStrategyUseIP QueryStrategy = "UseIP"
+	// QueryStrategyUseIPv4 only queries A records.
+	QueryStrategyUseIPv4 QueryStrategy = "UseIPv4"
+	// QueryStrategyUseIPv6 only queries AAAA records.
+	QueryStrategyUseIPv6 QueryStrategy = "UseIPv6"
+)
+
 // DefaultConfig returns a default configuration with a 5-second timeout
 // and the system's default DNS resolver.
 func DefaultConfig() *Config {
@@ -80,12 +213,30 @@ func NewClient(config *Config) (*Client, error) {
 		config.Timeout = 5 * time.Second
 	}
 
+	strategy := config.QueryStrategy
+	if strategy == "" {
+		strategy = QueryStrategyUseIP
+	}
+
+	disabled := make(map[RecordType]bool, len(config.DisabledRecordTypes))
+	for _, rt := range config.DisabledRecordTypes {
+		disabled[rt] = true
+	}
+
 	client := &Client{
-		timeout: config.Timeout,
+		timeout:     config.Timeout,
+		protocol:    config.Protocol,
+		strategy:    strategy,
+		parallelism: config.Parallelism,
+		validate:    config.Validate,
+		dnssec:      config.DNSSEC,
+		ednsDO:      config.EDNS0DO || config.Validate || config.DNSSEC,
+		ednsCD:      config.EDNS0CD,
+		disabled:    disabled,
 	}
 
 	if config.CustomResolver != "" {
-		client.resolver = &net.Resolver{
+		client.netResolver = &net.Resolver{
 			PreferGo: true,
 			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
 				d := net.Dialer{
@@ -95,7 +246,29 @@ func NewClient(config *Config) (*Client, error) {
 			},
 		}
 	} else {
-		client.resolver = net.DefaultResolver
+		client.netResolver = net.DefaultResolver
+	}
+
+	if config.Resolver != nil {
+		client.resolver = config.Resolver
+	}
+
+	raw, err := newRawResolver(config.CustomResolver, config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize wire-protocol resolver: %w", err)
+	}
+	client.raw = raw
+
+	if len(config.Transports) > 0 {
+		multi, err := newMultiTransportResolver(config.Transports, config.Strategy, config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize transports: %w", err)
+		}
+		client.multi = multi
+	}
+
+	if config.Cache.Enabled {
+		client.cache = newResultCache(config.Cache)
 	}
 
 	return client, nil
@@ -104,6 +277,10 @@ func NewClient(config *Config) (*Client, error) {
 // Lookup performs a DNS lookup for the specified domain and record type.
 // If ctx is nil, a new context with the client's timeout is created.
 // Returns a LookupResult containing the query results or an error if the lookup fails.
+// When Config.Cache is enabled, repeated lookups for the same (domain,
+// recordType) within the record's TTL are served from cache; concurrent
+// callers for the same key collapse into a single upstream query.
+// LookupResult.Metadata["cache"] reports "hit" or "miss".
 func (c *Client) Lookup(ctx context.Context, domain string, recordType RecordType) (*LookupResult, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
@@ -111,6 +288,55 @@ func (c *Client) Lookup(ctx context.Context, domain string, recordType RecordTyp
 		defer cancel()
 	}
 
+	var result *LookupResult
+	var err error
+
+	if c.cache == nil {
+		result, err = c.lookupUncached(ctx, domain, recordType)
+	} else {
+		var hit bool
+		result, err, hit = c.cache.lookup(key(domain, recordType), func() (*LookupResult, error) {
+			return c.lookupUncached(ctx, domain, recordType)
+		})
+		if result != nil {
+			if hit {
+				result.Metadata["cache"] = "hit"
+			} else {
+				result.Metadata["cache"] = "miss"
+			}
+		}
+	}
+
+	if err == nil && c.raw != nil && c.resolver == nil && result != nil {
+		if c.validate {
+			status, trace, verr := c.validateChain(ctx, domain, recordType)
+			result.AuthenticationStatus = status
+			result.ChainTrace = trace
+			if verr != nil {
+				result.Metadata["dnssec_error"] = verr.Error()
+			}
+		}
+		if c.dnssec {
+			dnssecResult, derr := c.queryAD(ctx, domain, recordType)
+			if derr != nil {
+				result.Metadata["dnssec_ad_error"] = derr.Error()
+			} else {
+				result.DNSSEC = dnssecResult
+			}
+		}
+	}
+
+	return result, err
+}
+
+// lookupUncached performs the actual DNS query, bypassing the cache.
+func (c *Client) lookupUncached(ctx context.Context, domain string, recordType RecordType) (*LookupResult, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+	}
+
 	result := &LookupResult{
 		Domain:     domain,
 		RecordType: recordType,
@@ -118,6 +344,18 @@ func (c *Client) Lookup(ctx context.Context, domain string, recordType RecordTyp
 		Metadata:   make(map[string]interface{}),
 	}
 
+	if c.resolver != nil {
+		return c.lookupViaResolver(ctx, domain, recordType, result)
+	}
+
+	if c.multi != nil {
+		return c.lookupViaTransports(ctx, domain, recordType, result)
+	}
+
+	if rawRecordTypes[recordType] || c.protocol == "miekg" {
+		return c.lookupRaw(ctx, domain, recordType, result)
+	}
+
 	var err error
 
 	switch recordType {
@@ -159,6 +397,11 @@ func (c *Client) Lookup(ctx context.Context, domain string, recordType RecordTyp
 
 // LookupAll performs lookups for all common record types (A, AAAA, CNAME, MX, NS, TXT).
 // If ctx is nil, a new context with extended timeout is created.
+// Queries fan out over a shared context with bounded concurrency (see
+// Config.Parallelism) instead of running one at a time, and Config.QueryStrategy
+// is honored: AAAA is skipped under UseIPv4, A is skipped under UseIPv6.
+// Config.DisabledRecordTypes is also honored, suppressing any record type
+// listed there regardless of strategy.
 // Returns a map of record types to their results, continuing even if individual lookups fail.
 func (c *Client) LookupAll(ctx context.Context, domain string) (map[RecordType]*LookupResult, error) {
 	if ctx == nil {
@@ -167,7 +410,6 @@ func (c *Client) LookupAll(ctx context.Context, domain string) (map[RecordType]*
 		defer cancel()
 	}
 
-	results := make(map[RecordType]*LookupResult)
 	recordTypes := []RecordType{
 		RecordTypeA,
 		RecordTypeAAAA,
@@ -176,22 +418,106 @@ func (c *Client) LookupAll(ctx context.Context, domain string) (map[RecordType]*
 		RecordTypeNS,
 		RecordTypeTXT,
 	}
+	recordTypes = c.filterByStrategy(recordTypes)
+	recordTypes = c.filterDisabled(recordTypes)
+
+	type outcome struct {
+		rt     RecordType
+		result *LookupResult
+		err    error
+	}
+
+	limit := c.parallelism
+	if limit <= 0 {
+		limit = len(recordTypes)
+	}
+	sem := make(chan struct{}, limit)
+	out := make(chan outcome, len(recordTypes))
 
 	for _, rt := range recordTypes {
-		result, err := c.Lookup(ctx, domain, rt)
-		if err != nil {
+		rt := rt
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			result, err := c.Lookup(ctx, domain, rt)
+			out <- outcome{rt: rt, result: result, err: err}
+		}()
+	}
+
+	results := make(map[RecordType]*LookupResult, len(recordTypes))
+	for range recordTypes {
+		o := <-out
+		if o.err != nil && o.result != nil {
 			// Continue even if one lookup fails
-			result.Error = err.Error()
+			o.result.Error = o.err.Error()
 		}
-		results[rt] = result
+		results[o.rt] = o.result
 	}
 
 	return results, nil
 }
 
+// filterByStrategy drops A or AAAA from recordTypes per c.strategy.
+func (c *Client) filterByStrategy(recordTypes []RecordType) []RecordType {
+	if c.strategy == QueryStrategyUseIP || c.strategy == "" {
+		return recordTypes
+	}
+
+	filtered := recordTypes[:0:0]
+	for _, rt := range recordTypes {
+		if c.strategy == QueryStrategyUseIPv4 && rt == RecordTypeAAAA {
+			continue
+		}
+		if c.strategy == QueryStrategyUseIPv6 && rt == RecordTypeA {
+			continue
+		}
+		filtered = append(filtered, rt)
+	}
+	return filtered
+}
+
+// filterDisabled drops any record type listed in Config.DisabledRecordTypes
+// from recordTypes.
+func (c *Client) filterDisabled(recordTypes []RecordType) []RecordType {
+	if len(c.disabled) == 0 {
+		return recordTypes
+	}
+
+	filtered := recordTypes[:0:0]
+	for _, rt := range recordTypes {
+		if c.disabled[rt] {
+			continue
+		}
+		filtered = append(filtered, rt)
+	}
+	return filtered
+}
+
+// Resolve performs a hostname-to-IP lookup and returns the resolved
+// addresses as net.IP, already filtered by Config.QueryStrategy (both
+// families under UseIP, the default; only one family under UseIPv4/UseIPv6).
+// It's a thin convenience wrapper around LookupHost for callers that just
+// want addresses rather than a full LookupResult.
+func (c *Client) Resolve(ctx context.Context, host string) ([]net.IP, error) {
+	result, err := c.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	ips := make([]net.IP, 0, len(result.Records))
+	for _, rec := range result.Records {
+		if ip := net.ParseIP(rec); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
+
 // LookupSRV performs a SRV record lookup for the specified service, protocol, and name.
 // For example, service="_http", proto="_tcp", name="example.com".
 // If ctx is nil, a new context with the client's timeout is created.
+// Like lookupUncached, this is routed through Config.Resolver when one is
+// set, rather than always going to the network via netResolver.
 func (c *Client) LookupSRV(ctx context.Context, service, proto, name string) (*LookupResult, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
@@ -206,7 +532,15 @@ func (c *Client) LookupSRV(ctx context.Context, service, proto, name string) (*L
 		Metadata:   make(map[string]interface{}),
 	}
 
-	_, addrs, err := c.resolver.LookupSRV(ctx, service, proto, name)
+	lookupSRV := c.netResolver.LookupSRV
+	if c.resolver != nil {
+		lookupSRV = func(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+			addrs, err := c.resolver.LookupSRV(ctx, service, proto, name)
+			return "", addrs, err
+		}
+	}
+
+	_, addrs, err := lookupSRV(ctx, service, proto, name)
 	if err != nil {
 		result.Error = err.Error()
 		return result, err
@@ -222,7 +556,7 @@ func (c *Client) LookupSRV(ctx context.Context, service, proto, name string) (*L
 
 // lookupA performs an A record lookup and returns IPv4 addresses.
 func (c *Client) lookupA(ctx context.Context, domain string) ([]string, error) {
-	ips, err := c.resolver.LookupIP(ctx, "ip4", domain)
+	ips, err := c.netResolver.LookupIP(ctx, "ip4", domain)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +570,7 @@ func (c *Client) lookupA(ctx context.Context, domain string) ([]string, error) {
 
 // lookupAAAA performs an AAAA record lookup and returns IPv6 addresses.
 func (c *Client) lookupAAAA(ctx context.Context, domain string) ([]string, error) {
-	ips, err := c.resolver.LookupIP(ctx, "ip6", domain)
+	ips, err := c.netResolver.LookupIP(ctx, "ip6", domain)
 	if err != nil {
 		return nil, err
 	}
@@ -250,7 +584,7 @@ func (c *Client) lookupAAAA(ctx context.Context, domain string) ([]string, error
 
 // lookupCNAME performs a CNAME record lookup and returns the canonical name.
 func (c *Client) lookupCNAME(ctx context.Context, domain string) ([]string, error) {
-	cname, err := c.resolver.LookupCNAME(ctx, domain)
+	cname, err := c.netResolver.LookupCNAME(ctx, domain)
 	if err != nil {
 		return nil, err
 	}
@@ -259,12 +593,12 @@ func (c *Client) lookupCNAME(ctx context.Context, domain string) ([]string, erro
 
 // lookupMX performs an MX record lookup and returns mail exchange records.
 func (c *Client) lookupMX(ctx context.Context, domain string) ([]*net.MX, error) {
-	return c.resolver.LookupMX(ctx, domain)
+	return c.netResolver.LookupMX(ctx, domain)
 }
 
 // lookupNS performs an NS record lookup and returns name server records.
 func (c *Client) lookupNS(ctx context.Context, domain string) ([]string, error) {
-	nss, err := c.resolver.LookupNS(ctx, domain)
+	nss, err := c.netResolver.LookupNS(ctx, domain)
 	if err != nil {
 		return nil, err
 	}
@@ -278,10 +612,10 @@ func (c *Client) lookupNS(ctx context.Context, domain string) ([]string, error)
 
 // lookupTXT performs a TXT record lookup and returns text records.
 func (c *Client) lookupTXT(ctx context.Context, domain string) ([]string, error) {
-	return c.resolver.LookupTXT(ctx, domain)
+	return c.netResolver.LookupTXT(ctx, domain)
 }
 
 // lookupPTR performs a PTR (reverse DNS) lookup for an IP address.
 func (c *Client) lookupPTR(ctx context.Context, ip string) ([]string, error) {
-	return c.resolver.LookupAddr(ctx, ip)
+	return c.netResolver.LookupAddr(ctx, ip)
 }
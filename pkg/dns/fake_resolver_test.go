@@ -0,0 +1,137 @@
+package dns
+
+import (
+	"context"
+	"testing"
+)
+
+func TestClientWithFakeResolver(t *testing.T) {
+	fake := NewFakeResolver()
+	fake.Register("example.com", RecordTypeA, FakeAnswer{
+		Records: []ResourceRecord{{Name: "example.com", Type: RecordTypeA, Data: map[string]string{"rdata": "1.2.3.4"}}},
+	})
+	fake.Register("nxdomain.example", RecordTypeA, FakeAnswer{Err: ErrNXDomain})
+
+	client, err := NewClient(&Config{Resolver: fake})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	result, err := client.Lookup(context.Background(), "example.com", RecordTypeA)
+	if err != nil {
+		t.Fatalf("Lookup() error = %v", err)
+	}
+	if len(result.Records) != 1 || result.Records[0] != "1.2.3.4" {
+		t.Errorf("Lookup() records = %v, want [1.2.3.4]", result.Records)
+	}
+
+	_, err = client.Lookup(context.Background(), "nxdomain.example", RecordTypeA)
+	if err != ErrNXDomain {
+		t.Errorf("Lookup() error = %v, want ErrNXDomain", err)
+	}
+}
+
+func TestFakeResolverCNAMEChain(t *testing.T) {
+	fake := NewFakeResolver()
+	fake.RegisterCNAMEChain("www.example.com", "example.com", "1.2.3.4")
+
+	ips, err := fake.LookupIP(context.Background(), "ip4", "www.example.com")
+	if err != nil {
+		t.Fatalf("LookupIP() error = %v", err)
+	}
+	if len(ips) != 1 || ips[0].String() != "1.2.3.4" {
+		t.Errorf("LookupIP() = %v, want [1.2.3.4]", ips)
+	}
+}
+
+func TestClientResolve(t *testing.T) {
+	fake := NewFakeResolver()
+	fake.Register("example.com", RecordTypeA, FakeAnswer{
+		Records: []ResourceRecord{{Name: "example.com", Type: RecordTypeA, Data: map[string]string{"rdata": "1.2.3.4"}}},
+	})
+
+	client, err := NewClient(&Config{Resolver: fake, QueryStrategy: QueryStrategyUseIPv4})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	ips, err := client.Resolve(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if len(ips) != 1 || ips[0].String() != "1.2.3.4" {
+		t.Errorf("Resolve() = %v, want [1.2.3.4]", ips)
+	}
+}
+
+func TestClientLookupCacheHitTwice(t *testing.T) {
+	fake := NewFakeResolver()
+	fake.Register("example.com", RecordTypeA, FakeAnswer{
+		Records: []ResourceRecord{{Name: "example.com", Type: RecordTypeA, Data: map[string]string{"rdata": "1.2.3.4"}}},
+	})
+
+	client, err := NewClient(&Config{Resolver: fake, Cache: CacheConfig{Enabled: true}})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	first, err := client.Lookup(context.Background(), "example.com", RecordTypeA)
+	if err != nil {
+		t.Fatalf("first Lookup() error = %v", err)
+	}
+	if first.Metadata["cache"] != "miss" {
+		t.Errorf("first Lookup() Metadata[cache] = %v, want miss", first.Metadata["cache"])
+	}
+
+	// The second call serves the cache-hit path, whose LookupResult comes
+	// back from a JSON round-trip through the store; this must not panic
+	// writing into a nil Metadata map.
+	second, err := client.Lookup(context.Background(), "example.com", RecordTypeA)
+	if err != nil {
+		t.Fatalf("second Lookup() error = %v", err)
+	}
+	if second.Metadata["cache"] != "hit" {
+		t.Errorf("second Lookup() Metadata[cache] = %v, want hit", second.Metadata["cache"])
+	}
+}
+
+func TestClientLookupSRVUsesFakeResolver(t *testing.T) {
+	fake := NewFakeResolver()
+	fake.Register("_http._tcp.example.com", RecordTypeSRV, FakeAnswer{
+		Records: []ResourceRecord{{Name: "_http._tcp.example.com", Type: RecordTypeSRV, Data: map[string]string{"rdata": "srv.example.com"}}},
+	})
+
+	client, err := NewClient(&Config{Resolver: fake})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+
+	// Hitting the real network here (via netResolver) would fail/hang in a
+	// sandboxed test environment, so a result at all proves LookupSRV
+	// dispatched through c.resolver rather than ignoring it.
+	result, err := client.LookupSRV(context.Background(), "http", "tcp", "example.com")
+	if err != nil {
+		t.Fatalf("LookupSRV() error = %v", err)
+	}
+	if len(result.SRVRecords) != 1 || result.SRVRecords[0].Target != "srv.example.com" {
+		t.Errorf("LookupSRV() SRVRecords = %v, want one record targeting srv.example.com", result.SRVRecords)
+	}
+}
+
+func TestHostsResolver(t *testing.T) {
+	r := NewHostsResolver(map[string][]string{
+		"example.com": {"1.2.3.4", "::1"},
+	})
+
+	ips, err := r.LookupIP(context.Background(), "ip4", "example.com")
+	if err != nil {
+		t.Fatalf("LookupIP() error = %v", err)
+	}
+	if len(ips) != 1 || ips[0].String() != "1.2.3.4" {
+		t.Errorf("LookupIP(ip4) = %v, want [1.2.3.4]", ips)
+	}
+
+	if _, err := r.LookupIP(context.Background(), "ip4", "unknown.example"); err == nil {
+		t.Error("expected error for unregistered host")
+	}
+}
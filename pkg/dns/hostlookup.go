@@ -0,0 +1,121 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+)
+
+// LookupHost issues A and AAAA queries for domain in parallel and returns a
+// single LookupResult with both families merged, honoring
+// Config.QueryStrategy (UseIPv4/UseIPv6 skip the other family entirely).
+// The merged Records are ordered per a simplified approximation of the RFC
+// 6724 address-selection rules: addresses are grouped by scope (global
+// first, then private/ULA and loopback, then link-local), without
+// implementing the full RFC 6724 policy table.
+// Per-family errors, if any, are recorded in Metadata["ipv4_error"] /
+// Metadata["ipv6_error"] rather than failing the whole call, mirroring
+// LookupAll's continue-on-error behavior.
+func (c *Client) LookupHost(ctx context.Context, domain string) (*LookupResult, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+	}
+
+	result := &LookupResult{
+		Domain:     domain,
+		RecordType: RecordTypeA,
+		Metadata:   make(map[string]interface{}),
+	}
+
+	wantV4 := c.strategy != QueryStrategyUseIPv6
+	wantV6 := c.strategy != QueryStrategyUseIPv4
+
+	type familyResult struct {
+		family string
+		result *LookupResult
+		err    error
+	}
+
+	var jobs []RecordType
+	if wantV4 {
+		jobs = append(jobs, RecordTypeA)
+	}
+	if wantV6 {
+		jobs = append(jobs, RecordTypeAAAA)
+	}
+	if len(jobs) == 0 {
+		return result, fmt.Errorf("query strategy %q disables both address families", c.strategy)
+	}
+
+	limit := c.parallelism
+	if limit <= 0 {
+		limit = len(jobs)
+	}
+	sem := make(chan struct{}, limit)
+	out := make(chan familyResult, len(jobs))
+	for _, rt := range jobs {
+		rt := rt
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			res, err := c.Lookup(ctx, domain, rt)
+			family := "ipv4"
+			if rt == RecordTypeAAAA {
+				family = "ipv6"
+			}
+			out <- familyResult{family: family, result: res, err: err}
+		}()
+	}
+
+	var all []net.IP
+	for range jobs {
+		fr := <-out
+		if fr.err != nil {
+			result.Metadata[fr.family+"_error"] = fr.err.Error()
+			continue
+		}
+		for _, rec := range fr.result.Records {
+			if ip := net.ParseIP(rec); ip != nil {
+				all = append(all, ip)
+			}
+		}
+	}
+
+	sortByScope(all)
+
+	for _, ip := range all {
+		result.Records = append(result.Records, ip.String())
+	}
+
+	if len(result.Records) == 0 {
+		err := fmt.Errorf("no addresses found for %s", domain)
+		result.Error = err.Error()
+		return result, err
+	}
+
+	return result, nil
+}
+
+// addressScope buckets an IP the way RFC 6724's scope comparisons do, just
+// enough to prefer global addresses over link-local/ULA ones.
+func addressScope(ip net.IP) int {
+	switch {
+	case ip.IsLinkLocalUnicast(), ip.IsLinkLocalMulticast():
+		return 0
+	case ip.IsPrivate(), ip.IsLoopback():
+		return 1
+	default:
+		return 2 // global
+	}
+}
+
+// sortByScope orders ips with the widest-scope (most likely to be globally
+// reachable) addresses first, stable within a scope.
+func sortByScope(ips []net.IP) {
+	sort.SliceStable(ips, func(i, j int) bool {
+		return addressScope(ips[i]) > addressScope(ips[j])
+	})
+}
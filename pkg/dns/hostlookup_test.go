@@ -0,0 +1,60 @@
+package dns
+
+import (
+	"net"
+	"testing"
+)
+
+func TestSortByScopePrefersGlobal(t *testing.T) {
+	ips := []net.IP{
+		net.ParseIP("fe80::1"),
+		net.ParseIP("8.8.8.8"),
+		net.ParseIP("192.168.1.1"),
+	}
+	sortByScope(ips)
+
+	if ips[0].String() != "8.8.8.8" {
+		t.Errorf("expected global address first, got %s", ips[0])
+	}
+	if ips[len(ips)-1].String() != "fe80::1" {
+		t.Errorf("expected link-local address last, got %s", ips[len(ips)-1])
+	}
+}
+
+func TestFilterByStrategy(t *testing.T) {
+	base := []RecordType{RecordTypeA, RecordTypeAAAA, RecordTypeCNAME}
+
+	v4 := (&Client{strategy: QueryStrategyUseIPv4}).filterByStrategy(base)
+	for _, rt := range v4 {
+		if rt == RecordTypeAAAA {
+			t.Error("UseIPv4 should have dropped AAAA")
+		}
+	}
+
+	v6 := (&Client{strategy: QueryStrategyUseIPv6}).filterByStrategy(base)
+	for _, rt := range v6 {
+		if rt == RecordTypeA {
+			t.Error("UseIPv6 should have dropped A")
+		}
+	}
+}
+
+func TestFilterDisabled(t *testing.T) {
+	base := []RecordType{RecordTypeA, RecordTypeAAAA, RecordTypeCNAME}
+
+	c := &Client{disabled: map[RecordType]bool{RecordTypeCNAME: true}}
+	filtered := c.filterDisabled(base)
+	for _, rt := range filtered {
+		if rt == RecordTypeCNAME {
+			t.Error("expected CNAME to be filtered out")
+		}
+	}
+	if len(filtered) != 2 {
+		t.Errorf("len(filtered) = %d, want 2", len(filtered))
+	}
+
+	none := (&Client{}).filterDisabled(base)
+	if len(none) != len(base) {
+		t.Error("expected no filtering when nothing is disabled")
+	}
+}
@@ -0,0 +1,91 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+)
+
+// HostsResolver is an in-memory Resolver backed by a static host->addresses
+// map, e.g. {"example.com": {"1.2.3.4", "5.6.7.8"}}, that never touches the
+// network. It only answers A/AAAA lookups (via LookupIP); every other
+// method returns an error, since a hosts file has no concept of MX/NS/etc.
+type HostsResolver struct {
+	hosts map[string][]net.IP
+}
+
+// NewHostsResolver builds a HostsResolver from a host -> address-list map.
+// Invalid IP strings are skipped.
+func NewHostsResolver(hosts map[string][]string) *HostsResolver {
+	r := &HostsResolver{hosts: make(map[string][]net.IP, len(hosts))}
+	for host, addrs := range hosts {
+		for _, a := range addrs {
+			if ip := net.ParseIP(a); ip != nil {
+				r.hosts[host] = append(r.hosts[host], ip)
+			}
+		}
+	}
+	return r
+}
+
+func (r *HostsResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	ips, ok := r.hosts[host]
+	if !ok {
+		return nil, fmt.Errorf("hosts resolver: no entry for %s", host)
+	}
+
+	wantV4 := network != "ip6"
+	wantV6 := network != "ip4"
+	var filtered []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil && wantV4 {
+			filtered = append(filtered, ip)
+		} else if ip.To4() == nil && wantV6 {
+			filtered = append(filtered, ip)
+		}
+	}
+	return filtered, nil
+}
+
+func (r *HostsResolver) LookupMX(context.Context, string) ([]*net.MX, error) {
+	return nil, fmt.Errorf("hosts resolver: MX lookups are not supported")
+}
+
+func (r *HostsResolver) LookupNS(context.Context, string) ([]*net.NS, error) {
+	return nil, fmt.Errorf("hosts resolver: NS lookups are not supported")
+}
+
+func (r *HostsResolver) LookupTXT(context.Context, string) ([]string, error) {
+	return nil, fmt.Errorf("hosts resolver: TXT lookups are not supported")
+}
+
+func (r *HostsResolver) LookupCNAME(context.Context, string) (string, error) {
+	return "", fmt.Errorf("hosts resolver: CNAME lookups are not supported")
+}
+
+func (r *HostsResolver) LookupSRV(context.Context, string, string, string) ([]*net.SRV, error) {
+	return nil, fmt.Errorf("hosts resolver: SRV lookups are not supported")
+}
+
+func (r *HostsResolver) LookupAddr(context.Context, string) ([]string, error) {
+	return nil, fmt.Errorf("hosts resolver: reverse lookups are not supported")
+}
+
+func (r *HostsResolver) Query(ctx context.Context, name string, recordType RecordType) ([]ResourceRecord, error) {
+	if recordType != RecordTypeA && recordType != RecordTypeAAAA {
+		return nil, fmt.Errorf("hosts resolver: %s lookups are not supported", recordType)
+	}
+	network := "ip4"
+	if recordType == RecordTypeAAAA {
+		network = "ip6"
+	}
+	ips, err := r.LookupIP(ctx, network, name)
+	if err != nil {
+		return nil, err
+	}
+	rrs := make([]ResourceRecord, 0, len(ips))
+	for _, ip := range ips {
+		rrs = append(rrs, ResourceRecord{Name: name, Type: recordType, Data: map[string]string{"rdata": ip.String()}})
+	}
+	return rrs, nil
+}
@@ -0,0 +1,220 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+// rawResolver issues DNS queries directly over the wire protocol using
+// miekg/dns, bypassing net.Resolver. It is used for record types the
+// standard library has no concept of, for populating per-record TTLs, and
+// whenever Config.Protocol is set to "miekg".
+type rawResolver struct {
+	server  string
+	timeout time.Duration
+}
+
+// newRawResolver builds a rawResolver targeting server (host:port). When
+// server is empty, it reads the system's /etc/resolv.conf, mirroring the
+// "CustomResolver or /etc/resolv.conf" behavior requested for the client.
+func newRawResolver(server string, timeout time.Duration) (*rawResolver, error) {
+	if server == "" {
+		conf, err := miekgdns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil || conf == nil || len(conf.Servers) == 0 {
+			// No usable resolv.conf (e.g. non-Unix systems, sandboxes without
+			// one). Fall back to a well-known public resolver rather than
+			// failing client construction.
+			server = "8.8.8.8:53"
+		} else {
+			server = fmt.Sprintf("%s:%s", conf.Servers[0], conf.Port)
+		}
+	}
+
+	return &rawResolver{server: server, timeout: timeout}, nil
+}
+
+// NegativeError wraps a negative (non-success rcode) DNS response, carrying
+// the authority section's SOA MINIMUM field when the response included one,
+// so the result cache can honor RFC 2308's negative-caching TTL instead of
+// falling back to CacheConfig.NegativeTTL.
+type NegativeError struct {
+	Err       error
+	SOAMinTTL time.Duration
+}
+
+func (e *NegativeError) Error() string { return e.Err.Error() }
+func (e *NegativeError) Unwrap() error { return e.Err }
+
+// negativeErrorFromReply wraps err with the SOA MINIMUM from reply's
+// authority section, if present.
+func negativeErrorFromReply(reply *miekgdns.Msg, err error) error {
+	for _, rr := range reply.Ns {
+		if soa, ok := rr.(*miekgdns.SOA); ok {
+			return &NegativeError{Err: err, SOAMinTTL: time.Duration(soa.Minttl) * time.Second}
+		}
+	}
+	return err
+}
+
+// recordTypeToQtype maps a RecordType to its miekg/dns query type constant.
+var recordTypeToQtype = map[RecordType]uint16{
+	RecordTypeA:      miekgdns.TypeA,
+	RecordTypeAAAA:   miekgdns.TypeAAAA,
+	RecordTypeCNAME:  miekgdns.TypeCNAME,
+	RecordTypeMX:     miekgdns.TypeMX,
+	RecordTypeNS:     miekgdns.TypeNS,
+	RecordTypeTXT:    miekgdns.TypeTXT,
+	RecordTypeSOA:    miekgdns.TypeSOA,
+	RecordTypePTR:    miekgdns.TypePTR,
+	RecordTypeSRV:    miekgdns.TypeSRV,
+	RecordTypeCAA:    miekgdns.TypeCAA,
+	RecordTypeDS:     miekgdns.TypeDS,
+	RecordTypeDNSKEY: miekgdns.TypeDNSKEY,
+	RecordTypeNAPTR:  miekgdns.TypeNAPTR,
+	RecordTypeTLSA:   miekgdns.TypeTLSA,
+	RecordTypeHINFO:  miekgdns.TypeHINFO,
+}
+
+// exchange sends msg over UDP and, if the response comes back truncated,
+// retries the same query over TCP. It returns the reply and the transport
+// that ultimately produced it ("udp" or "tcp").
+func (r *rawResolver) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, string, error) {
+	deadline := r.timeout
+	if dl, ok := ctx.Deadline(); ok {
+		if d := time.Until(dl); d > 0 {
+			deadline = d
+		}
+	}
+
+	udp := &miekgdns.Client{Net: "udp", Timeout: deadline}
+	reply, _, err := udp.ExchangeContext(ctx, msg, r.server)
+	if err != nil {
+		return nil, "", fmt.Errorf("dns query to %s failed: %w", r.server, err)
+	}
+
+	if !reply.Truncated {
+		return reply, "udp", nil
+	}
+
+	tcp := &miekgdns.Client{Net: "tcp", Timeout: deadline}
+	reply, _, err = tcp.ExchangeContext(ctx, msg, r.server)
+	if err != nil {
+		return nil, "", fmt.Errorf("truncated dns query retry over tcp to %s failed: %w", r.server, err)
+	}
+	return reply, "tcp", nil
+}
+
+// lookupRaw performs a lookup through the wire-protocol resolver and fills
+// in the parts of LookupResult net.Resolver cannot provide: per-record TTL,
+// the transport actually used, and the typed Raw resource records.
+func (c *Client) lookupRaw(ctx context.Context, domain string, recordType RecordType, result *LookupResult) (*LookupResult, error) {
+	qtype, ok := recordTypeToQtype[recordType]
+	if !ok {
+		err := fmt.Errorf("unsupported record type: %s", recordType)
+		result.Error = err.Error()
+		return result, err
+	}
+
+	msg := new(miekgdns.Msg)
+	msg.SetQuestion(miekgdns.Fqdn(domain), qtype)
+	msg.RecursionDesired = true
+
+	reply, transport, err := c.raw.exchange(ctx, msg)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	result.Metadata["transport"] = transport
+
+	if reply.Rcode != miekgdns.RcodeSuccess {
+		err := fmt.Errorf("dns query failed with rcode %s", miekgdns.RcodeToString[reply.Rcode])
+		result.Error = err.Error()
+		return result, negativeErrorFromReply(reply, err)
+	}
+
+	var minTTL time.Duration
+	for i, rr := range reply.Answer {
+		rec := resourceRecordFromRR(rr, recordType)
+		result.Raw = append(result.Raw, rec)
+		result.Records = append(result.Records, rrDataString(rr))
+		if i == 0 || rec.TTL < minTTL {
+			minTTL = rec.TTL
+		}
+	}
+	result.TTL = minTTL
+
+	return result, nil
+}
+
+// resourceRecordFromRR converts a miekg/dns answer RR into our
+// transport-agnostic ResourceRecord, extracting the fields of the payload
+// that don't fit the flattened string-list model.
+func resourceRecordFromRR(rr miekgdns.RR, recordType RecordType) ResourceRecord {
+	hdr := rr.Header()
+	rec := ResourceRecord{
+		Name:  strings.TrimSuffix(hdr.Name, "."),
+		Type:  recordType,
+		Class: miekgdns.ClassToString[hdr.Class],
+		TTL:   time.Duration(hdr.Ttl) * time.Second,
+		Data:  make(map[string]string),
+	}
+
+	switch v := rr.(type) {
+	case *miekgdns.CAA:
+		rec.Data["flag"] = fmt.Sprintf("%d", v.Flag)
+		rec.Data["tag"] = v.Tag
+		rec.Data["value"] = v.Value
+	case *miekgdns.DS:
+		rec.Data["key_tag"] = fmt.Sprintf("%d", v.KeyTag)
+		rec.Data["algorithm"] = fmt.Sprintf("%d", v.Algorithm)
+		rec.Data["digest_type"] = fmt.Sprintf("%d", v.DigestType)
+		rec.Data["digest"] = v.Digest
+	case *miekgdns.DNSKEY:
+		rec.Data["flags"] = fmt.Sprintf("%d", v.Flags)
+		rec.Data["protocol"] = fmt.Sprintf("%d", v.Protocol)
+		rec.Data["algorithm"] = fmt.Sprintf("%d", v.Algorithm)
+		rec.Data["public_key"] = v.PublicKey
+	case *miekgdns.NAPTR:
+		rec.Data["order"] = fmt.Sprintf("%d", v.Order)
+		rec.Data["preference"] = fmt.Sprintf("%d", v.Preference)
+		rec.Data["flags"] = v.Flags
+		rec.Data["service"] = v.Service
+		rec.Data["regexp"] = v.Regexp
+		rec.Data["replacement"] = v.Replacement
+	case *miekgdns.TLSA:
+		rec.Data["usage"] = fmt.Sprintf("%d", v.Usage)
+		rec.Data["selector"] = fmt.Sprintf("%d", v.Selector)
+		rec.Data["matching_type"] = fmt.Sprintf("%d", v.MatchingType)
+		rec.Data["certificate"] = v.Certificate
+	case *miekgdns.HINFO:
+		rec.Data["cpu"] = v.Cpu
+		rec.Data["os"] = v.Os
+	case *miekgdns.SOA:
+		rec.Data["ns"] = v.Ns
+		rec.Data["mbox"] = v.Mbox
+		rec.Data["serial"] = fmt.Sprintf("%d", v.Serial)
+		rec.Data["refresh"] = fmt.Sprintf("%d", v.Refresh)
+		rec.Data["retry"] = fmt.Sprintf("%d", v.Retry)
+		rec.Data["expire"] = fmt.Sprintf("%d", v.Expire)
+		rec.Data["minttl"] = fmt.Sprintf("%d", v.Minttl)
+	default:
+		rec.Data["rdata"] = rrDataString(rr)
+	}
+
+	return rec
+}
+
+// rrDataString renders the rdata portion of rr the way it would appear in
+// LookupResult.Records, i.e. without the owner/class/ttl header.
+func rrDataString(rr miekgdns.RR) string {
+	full := rr.String()
+	fields := strings.SplitN(full, "\t", 5)
+	if len(fields) == 5 {
+		return fields[4]
+	}
+	return full
+}
@@ -0,0 +1,46 @@
+package dns
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLookupSOA(t *testing.T) {
+	client, err := NewClient(DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	result, err := client.Lookup(ctx, "google.com", RecordTypeSOA)
+	if err != nil {
+		t.Logf("SOA lookup failed (network may be unavailable): %v", err)
+		return
+	}
+
+	if result.Metadata["transport"] == nil {
+		t.Error("expected Metadata[\"transport\"] to be populated for a wire-protocol lookup")
+	}
+	t.Logf("SOA records for google.com: %v (ttl=%s)", result.Records, result.TTL)
+}
+
+func TestLookupCAA(t *testing.T) {
+	client, err := NewClient(DefaultConfig())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	ctx := context.Background()
+	result, err := client.Lookup(ctx, "google.com", RecordTypeCAA)
+	if err != nil {
+		t.Logf("CAA lookup failed (network may be unavailable): %v", err)
+		return
+	}
+
+	for _, rr := range result.Raw {
+		if rr.Type != RecordTypeCAA {
+			t.Errorf("expected Raw record type CAA, got %s", rr.Type)
+		}
+	}
+	t.Logf("CAA records for google.com: %v", result.Records)
+}
@@ -0,0 +1,276 @@
+package dns
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// Resolver is the contract Client relies on to actually perform lookups.
+// NetResolver (the historical net.Resolver-backed behavior), MiekgResolver
+// (the wire-protocol path from raw.go), and FakeResolver (a canned-response
+// implementation for tests) all satisfy it. Set Config.Resolver to inject a
+// custom implementation, e.g. a hosts-file resolver that never touches the
+// network.
+//
+// This is an additive step towards routing every Client method through a
+// Resolver: today it's consulted when Config.Resolver is set, and Client
+// falls back to its existing net.Resolver/rawResolver/multiTransportResolver
+// plumbing otherwise so existing callers and tests are unaffected.
+type Resolver interface {
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+	LookupCNAME(ctx context.Context, name string) (string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) ([]*net.SRV, error)
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	// Query performs a generic lookup for name/qtype and returns the raw
+	// resource records, for record types (SOA, CAA, DS, DNSKEY, NAPTR,
+	// TLSA, HINFO, ...) that don't fit any of the typed methods above.
+	Query(ctx context.Context, name string, recordType RecordType) ([]ResourceRecord, error)
+}
+
+// NetResolver implements Resolver on top of the standard library's
+// net.Resolver, i.e. the behavior Client had before the wire-protocol and
+// encrypted-transport resolvers were added. It cannot supply per-record
+// TTLs or the record types net.Resolver itself doesn't support; Query
+// returns an error for those.
+type NetResolver struct {
+	resolver *net.Resolver
+}
+
+// NewNetResolver wraps resolver (net.DefaultResolver if nil).
+func NewNetResolver(resolver *net.Resolver) *NetResolver {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &NetResolver{resolver: resolver}
+}
+
+func (r *NetResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return r.resolver.LookupIP(ctx, network, host)
+}
+
+func (r *NetResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return r.resolver.LookupMX(ctx, name)
+}
+
+func (r *NetResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	return r.resolver.LookupNS(ctx, name)
+}
+
+func (r *NetResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return r.resolver.LookupTXT(ctx, name)
+}
+
+func (r *NetResolver) LookupCNAME(ctx context.Context, name string) (string, error) {
+	return r.resolver.LookupCNAME(ctx, name)
+}
+
+func (r *NetResolver) LookupSRV(ctx context.Context, service, proto, name string) ([]*net.SRV, error) {
+	_, addrs, err := r.resolver.LookupSRV(ctx, service, proto, name)
+	return addrs, err
+}
+
+func (r *NetResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return r.resolver.LookupAddr(ctx, addr)
+}
+
+func (r *NetResolver) Query(ctx context.Context, name string, recordType RecordType) ([]ResourceRecord, error) {
+	return nil, errUnsupportedByNetResolver(recordType)
+}
+
+// MiekgResolver implements Resolver on top of the wire-protocol rawResolver,
+// so it can answer every RecordType including the ones net.Resolver has no
+// concept of.
+type MiekgResolver struct {
+	raw *rawResolver
+}
+
+// NewMiekgResolver builds a MiekgResolver against server (host:port), or
+// /etc/resolv.conf when server is empty.
+func NewMiekgResolver(server string, timeout time.Duration) (*MiekgResolver, error) {
+	raw, err := newRawResolver(server, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &MiekgResolver{raw: raw}, nil
+}
+
+func (r *MiekgResolver) Query(ctx context.Context, name string, recordType RecordType) ([]ResourceRecord, error) {
+	result := &LookupResult{Metadata: make(map[string]interface{})}
+	c := &Client{raw: r.raw}
+	if _, err := c.lookupRaw(ctx, name, recordType, result); err != nil {
+		return nil, err
+	}
+	return result.Raw, nil
+}
+
+func (r *MiekgResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	recordType := RecordTypeA
+	if network == "ip6" {
+		recordType = RecordTypeAAAA
+	}
+	rrs, err := r.Query(ctx, host, recordType)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(rrs))
+	for _, rr := range rrs {
+		if ip := net.ParseIP(rr.Data["rdata"]); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips, nil
+}
+
+func (r *MiekgResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	rrs, err := r.Query(ctx, name, RecordTypeMX)
+	if err != nil {
+		return nil, err
+	}
+	mx := make([]*net.MX, 0, len(rrs))
+	for _, rr := range rrs {
+		mx = append(mx, &net.MX{Host: rr.Data["rdata"]})
+	}
+	return mx, nil
+}
+
+func (r *MiekgResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	rrs, err := r.Query(ctx, name, RecordTypeNS)
+	if err != nil {
+		return nil, err
+	}
+	ns := make([]*net.NS, 0, len(rrs))
+	for _, rr := range rrs {
+		ns = append(ns, &net.NS{Host: rr.Data["rdata"]})
+	}
+	return ns, nil
+}
+
+func (r *MiekgResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	rrs, err := r.Query(ctx, name, RecordTypeTXT)
+	if err != nil {
+		return nil, err
+	}
+	txt := make([]string, 0, len(rrs))
+	for _, rr := range rrs {
+		txt = append(txt, rr.Data["rdata"])
+	}
+	return txt, nil
+}
+
+func (r *MiekgResolver) LookupCNAME(ctx context.Context, name string) (string, error) {
+	rrs, err := r.Query(ctx, name, RecordTypeCNAME)
+	if err != nil {
+		return "", err
+	}
+	if len(rrs) == 0 {
+		return "", nil
+	}
+	return rrs[0].Data["rdata"], nil
+}
+
+func (r *MiekgResolver) LookupSRV(ctx context.Context, service, proto, name string) ([]*net.SRV, error) {
+	target := "_" + service + "._" + proto + "." + name
+	rrs, err := r.Query(ctx, target, RecordTypeSRV)
+	if err != nil {
+		return nil, err
+	}
+	srvs := make([]*net.SRV, 0, len(rrs))
+	for _, rr := range rrs {
+		srvs = append(srvs, &net.SRV{Target: rr.Data["rdata"]})
+	}
+	return srvs, nil
+}
+
+func (r *MiekgResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	rrs, err := r.Query(ctx, addr, RecordTypePTR)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(rrs))
+	for _, rr := range rrs {
+		names = append(names, rr.Data["rdata"])
+	}
+	return names, nil
+}
+
+// lookupViaResolver performs a lookup through Client.resolver (the Resolver
+// interface override), the same code paths NetResolver/MiekgResolver/
+// FakeResolver all exercise in tests.
+func (c *Client) lookupViaResolver(ctx context.Context, domain string, recordType RecordType, result *LookupResult) (*LookupResult, error) {
+	var err error
+
+	switch recordType {
+	case RecordTypeA:
+		result.Records, err = ipsToStrings(c.resolver.LookupIP(ctx, "ip4", domain))
+	case RecordTypeAAAA:
+		result.Records, err = ipsToStrings(c.resolver.LookupIP(ctx, "ip6", domain))
+	case RecordTypeCNAME:
+		var cname string
+		cname, err = c.resolver.LookupCNAME(ctx, domain)
+		if err == nil {
+			result.Records = []string{cname}
+		}
+	case RecordTypeMX:
+		result.MXRecords, err = c.resolver.LookupMX(ctx, domain)
+		if err == nil {
+			for _, mx := range result.MXRecords {
+				result.Records = append(result.Records, mx.Host)
+			}
+		}
+	case RecordTypeNS:
+		var nss []*net.NS
+		nss, err = c.resolver.LookupNS(ctx, domain)
+		if err == nil {
+			for _, ns := range nss {
+				result.NameServers = append(result.NameServers, ns.Host)
+			}
+			result.Records = result.NameServers
+		}
+	case RecordTypeTXT:
+		result.Records, err = c.resolver.LookupTXT(ctx, domain)
+	case RecordTypePTR:
+		result.Records, err = c.resolver.LookupAddr(ctx, domain)
+	default:
+		var rrs []ResourceRecord
+		rrs, err = c.resolver.Query(ctx, domain, recordType)
+		if err == nil {
+			result.Raw = rrs
+			for _, rr := range rrs {
+				result.Records = append(result.Records, rr.Data["rdata"])
+			}
+		}
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	return result, nil
+}
+
+func ipsToStrings(ips []net.IP, err error) ([]string, error) {
+	if err != nil {
+		return nil, err
+	}
+	records := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		records = append(records, ip.String())
+	}
+	return records, nil
+}
+
+func errUnsupportedByNetResolver(recordType RecordType) error {
+	return &unsupportedRecordTypeError{recordType: recordType}
+}
+
+type unsupportedRecordTypeError struct {
+	recordType RecordType
+}
+
+func (e *unsupportedRecordTypeError) Error() string {
+	return "net.Resolver has no equivalent for record type " + string(e.recordType) + "; use MiekgResolver"
+}
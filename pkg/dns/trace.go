@@ -0,0 +1,207 @@
+package dns
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+// rootHints are the IANA root server IPv4 addresses used as the starting
+// point for iterative resolution, mirroring dig +trace's built-in list.
+var rootHints = []string{
+	"198.41.0.4",     // a.root-servers.net
+	"199.9.14.201",   // b.root-servers.net
+	"192.33.4.12",    // c.root-servers.net
+	"199.7.91.13",    // d.root-servers.net
+	"192.203.230.10", // e.root-servers.net
+	"192.5.5.241",    // f.root-servers.net
+	"192.112.36.4",   // g.root-servers.net
+	"198.97.190.53",  // h.root-servers.net
+	"192.36.148.17",  // i.root-servers.net
+	"192.58.128.30",  // j.root-servers.net
+	"193.0.14.129",   // k.root-servers.net
+	"199.7.83.42",    // l.root-servers.net
+	"202.12.27.33",   // m.root-servers.net
+}
+
+// TraceStep records one hop of an iterative (dig +trace style) resolution:
+// the zone delegated at this hop, the nameservers delegated for it, which
+// one was actually queried, how long that query took, and the records it
+// returned (empty except on the final, authoritative hop).
+type TraceStep struct {
+	Zone          string
+	Nameservers   []string
+	QueriedServer string
+	RTT           time.Duration
+	Records       []ResourceRecord
+}
+
+// Trace performs a dig +trace style iterative resolution of (domain,
+// recordType): starting from the root hints, it sends the original question
+// non-recursively to each delegated zone's servers and follows the
+// referrals down the delegation chain, using glue
+// records when the referral carries them and falling back to a normal
+// lookup of the nameserver's own address when it doesn't (an
+// out-of-bailiwick nameserver), until an authoritative server answers the
+// original question.
+func (c *Client) Trace(ctx context.Context, domain string, recordType RecordType) ([]TraceStep, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+	}
+
+	qtype, ok := recordTypeToQtype[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type: %s", recordType)
+	}
+
+	fqdn := miekgdns.Fqdn(strings.ToLower(domain))
+	servers := withPort53All(rootHints)
+	zone := "."
+	visited := map[string]bool{}
+
+	var steps []TraceStep
+	for {
+		reply, queried, rtt, err := c.exchangeAny(ctx, servers, fqdn, qtype)
+		if err != nil {
+			return steps, fmt.Errorf("trace failed at zone %q: %w", zone, err)
+		}
+
+		step := TraceStep{Zone: zone, Nameservers: servers, QueriedServer: queried, RTT: rtt}
+
+		if len(reply.Answer) > 0 || len(reply.Ns) == 0 {
+			step.Records = rrsToResourceRecords(reply.Answer, recordType)
+			steps = append(steps, step)
+			return steps, nil
+		}
+		steps = append(steps, step)
+
+		nextZone, nsNames, glue := nextDelegation(reply)
+		if nextZone == "" || visited[nextZone] {
+			return steps, fmt.Errorf("trace stalled: no further delegation from zone %q", zone)
+		}
+		visited[nextZone] = true
+
+		resolved, err := c.resolveGlue(ctx, nsNames, glue)
+		if err != nil {
+			return steps, fmt.Errorf("failed to resolve nameservers for zone %s: %w", nextZone, err)
+		}
+
+		servers = resolved
+		zone = nextZone
+	}
+}
+
+// exchangeAny tries servers in order until one answers (fqdn, qtype)
+// non-recursively, returning the reply, the server that answered, and how
+// long the successful query took.
+func (c *Client) exchangeAny(ctx context.Context, servers []string, fqdn string, qtype uint16) (*miekgdns.Msg, string, time.Duration, error) {
+	msg := new(miekgdns.Msg)
+	msg.SetQuestion(fqdn, qtype)
+	msg.RecursionDesired = false
+
+	var lastErr error
+	for _, server := range servers {
+		raw, err := newRawResolver(server, c.timeout)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		start := time.Now()
+		reply, _, err := raw.exchange(ctx, msg)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return reply, server, time.Since(start), nil
+	}
+	return nil, "", 0, fmt.Errorf("all nameservers failed, last error: %w", lastErr)
+}
+
+// nextDelegation extracts the delegated zone, its NS names, and any glue
+// (A/AAAA records in the Additional section) from a referral reply.
+func nextDelegation(reply *miekgdns.Msg) (zone string, nsNames []string, glue map[string][]string) {
+	for _, rr := range reply.Ns {
+		ns, ok := rr.(*miekgdns.NS)
+		if !ok {
+			continue
+		}
+		if zone == "" {
+			zone = strings.TrimSuffix(ns.Hdr.Name, ".")
+		}
+		nsNames = append(nsNames, strings.TrimSuffix(ns.Ns, "."))
+	}
+
+	glue = make(map[string][]string)
+	for _, rr := range reply.Extra {
+		switch v := rr.(type) {
+		case *miekgdns.A:
+			name := strings.TrimSuffix(v.Hdr.Name, ".")
+			glue[name] = append(glue[name], v.A.String())
+		case *miekgdns.AAAA:
+			name := strings.TrimSuffix(v.Hdr.Name, ".")
+			glue[name] = append(glue[name], v.AAAA.String())
+		}
+	}
+
+	return zone, nsNames, glue
+}
+
+// resolveGlue turns a referral's NS names into queryable server addresses,
+// preferring glue records and falling back to a normal recursive lookup of
+// the nameserver's own address for out-of-bailiwick nameservers.
+func (c *Client) resolveGlue(ctx context.Context, nsNames []string, glue map[string][]string) ([]string, error) {
+	resolver := c.netResolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	var servers []string
+	for _, name := range nsNames {
+		if ips, ok := glue[name]; ok {
+			for _, ip := range ips {
+				servers = append(servers, withPort53(ip))
+			}
+			continue
+		}
+
+		ips, err := resolver.LookupHost(ctx, name)
+		if err != nil || len(ips) == 0 {
+			continue
+		}
+		servers = append(servers, withPort53(ips[0]))
+	}
+
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no resolvable nameserver address among %v", nsNames)
+	}
+	return servers, nil
+}
+
+func withPort53(ip string) string {
+	return net.JoinHostPort(ip, "53")
+}
+
+func withPort53All(ips []string) []string {
+	out := make([]string, len(ips))
+	for i, ip := range ips {
+		out[i] = withPort53(ip)
+	}
+	return out
+}
+
+// rrsToResourceRecords converts answer RRs into ResourceRecords, reusing the
+// same per-type field extraction as the raw resolver path.
+func rrsToResourceRecords(rrs []miekgdns.RR, recordType RecordType) []ResourceRecord {
+	recs := make([]ResourceRecord, 0, len(rrs))
+	for _, rr := range rrs {
+		recs = append(recs, resourceRecordFromRR(rr, recordType))
+	}
+	return recs
+}
@@ -0,0 +1,44 @@
+package dns
+
+import (
+	"net"
+	"testing"
+
+	miekgdns "github.com/miekg/dns"
+)
+
+func TestNextDelegation(t *testing.T) {
+	reply := new(miekgdns.Msg)
+	reply.Ns = []miekgdns.RR{
+		&miekgdns.NS{Hdr: miekgdns.RR_Header{Name: "example.com."}, Ns: "ns1.example.com."},
+		&miekgdns.NS{Hdr: miekgdns.RR_Header{Name: "example.com."}, Ns: "ns2.example.com."},
+	}
+	reply.Extra = []miekgdns.RR{
+		&miekgdns.A{Hdr: miekgdns.RR_Header{Name: "ns1.example.com."}, A: parseIP("192.0.2.1")},
+	}
+
+	zone, names, glue := nextDelegation(reply)
+	if zone != "example.com" {
+		t.Errorf("zone = %q, want example.com", zone)
+	}
+	if len(names) != 2 || names[0] != "ns1.example.com" || names[1] != "ns2.example.com" {
+		t.Errorf("nsNames = %v, want [ns1.example.com ns2.example.com]", names)
+	}
+	if ips := glue["ns1.example.com"]; len(ips) != 1 || ips[0] != "192.0.2.1" {
+		t.Errorf("glue[ns1.example.com] = %v, want [192.0.2.1]", ips)
+	}
+}
+
+func TestWithPort53All(t *testing.T) {
+	got := withPort53All([]string{"198.41.0.4", "199.9.14.201"})
+	want := []string{"198.41.0.4:53", "199.9.14.201:53"}
+	for i, g := range got {
+		if g != want[i] {
+			t.Errorf("withPort53All()[%d] = %q, want %q", i, g, want[i])
+		}
+	}
+}
+
+func parseIP(s string) net.IP {
+	return net.ParseIP(s)
+}
@@ -0,0 +1,452 @@
+package dns
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+// Strategy controls how a Client with multiple Transports picks among them
+// for a given query.
+type Strategy string
+
+const (
+	// StrategySequential tries each transport in order, falling back to the
+	// next one on failure. This is the default.
+	StrategySequential Strategy = "sequential"
+	// StrategyParallelRace fires the query at every transport simultaneously
+	// and returns whichever answer comes back first.
+	StrategyParallelRace Strategy = "parallel-race"
+	// StrategyRandom picks one transport at random per query.
+	StrategyRandom Strategy = "random"
+)
+
+// ResolverSpec describes a single upstream nameserver by URL rather than a
+// bare host:port, so encrypted transports can be configured alongside plain
+// DNS: "udp://1.1.1.1:53", "tcp://1.1.1.1:53", "tls://1.1.1.1:853" (DoT),
+// "https://cloudflare-dns.com/dns-query" (DoH), "quic://dns.adguard.com:853"
+// (DoQ).
+type ResolverSpec struct {
+	URL string
+	// BootstrapIPs resolves the hostname in URL without depending on DNS:
+	// the first entry replaces the URL's host when dialing udp/tcp/tls/quic
+	// upstreams, while the TLS server name stays the URL's hostname. Later
+	// entries are not used, and https (DoH) upstreams ignore BootstrapIPs
+	// and dial the URL's host as written.
+	BootstrapIPs []string
+	// TLSConfig is used for DoT/DoH/DoQ; when nil a sensible default
+	// (server name taken from the URL host) is used.
+	TLSConfig *tls.Config
+	// PinnedSPKI, when set, restricts DoT/DoH/DoQ certificate validation to
+	// certificates whose SubjectPublicKeyInfo hashes (base64-encoded SHA-256,
+	// as in HPKP) match one of these values, instead of the usual CA chain
+	// verification. Ignored if TLSConfig is also set.
+	PinnedSPKI []string
+}
+
+// transport is the minimal contract every upstream (UDP, TCP, DoT, DoH, DoQ)
+// implements so the multiTransportResolver can treat them uniformly.
+type transport interface {
+	// name identifies the transport for LookupResult.Metadata, e.g. "udp",
+	// "tcp", "dot", "doh", "doq".
+	name() string
+	// exchange sends msg and returns the reply.
+	exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error)
+}
+
+// multiTransportResolver fans a query out across one or more ResolverSpecs
+// according to a Strategy.
+type multiTransportResolver struct {
+	transports []transport
+	strategy   Strategy
+	timeout    time.Duration
+}
+
+// newMultiTransportResolver builds the concrete transport for each spec.
+func newMultiTransportResolver(specs []ResolverSpec, strategy Strategy, timeout time.Duration) (*multiTransportResolver, error) {
+	if strategy == "" {
+		strategy = StrategySequential
+	}
+
+	r := &multiTransportResolver{strategy: strategy, timeout: timeout}
+	for _, spec := range specs {
+		t, err := newTransport(spec, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build transport for %s: %w", spec.URL, err)
+		}
+		r.transports = append(r.transports, t)
+	}
+	return r, nil
+}
+
+// newTransport dispatches on the URL scheme to build the right transport.
+func newTransport(spec ResolverSpec, timeout time.Duration) (transport, error) {
+	u, err := url.Parse(spec.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver URL %q: %w", spec.URL, err)
+	}
+
+	addr := u.Host
+	if len(spec.BootstrapIPs) > 0 {
+		addr = bootstrapAddr(u, spec.BootstrapIPs[0])
+	}
+
+	switch u.Scheme {
+	case "udp", "":
+		udpAddr := withDefaultPort(addr, "53")
+		return &udpTransport{addr: udpAddr, timeout: timeout, tcpFallback: &tcpTransport{addr: udpAddr, timeout: timeout}}, nil
+	case "tcp":
+		return &tcpTransport{addr: withDefaultPort(addr, "53"), timeout: timeout}, nil
+	case "tls":
+		return &dotTransport{addr: withDefaultPort(addr, "853"), serverName: u.Hostname(), tlsConfig: tlsConfigFor(spec, u.Hostname()), timeout: timeout}, nil
+	case "https":
+		return &dohTransport{url: spec.URL, tlsConfig: tlsConfigFor(spec, u.Hostname()), timeout: timeout}, nil
+	case "quic":
+		return &doqTransport{addr: withDefaultPort(addr, "853"), serverName: u.Hostname(), tlsConfig: tlsConfigFor(spec, u.Hostname()), timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("unsupported resolver scheme %q", u.Scheme)
+	}
+}
+
+// tlsConfigFor returns spec.TLSConfig if set, otherwise a config pinning to
+// spec.PinnedSPKI if set, otherwise nil (letting each transport fall back to
+// its own default).
+func tlsConfigFor(spec ResolverSpec, serverName string) *tls.Config {
+	if spec.TLSConfig != nil {
+		return spec.TLSConfig
+	}
+	if len(spec.PinnedSPKI) > 0 {
+		return spkiPinnedTLSConfig(spec.PinnedSPKI, serverName)
+	}
+	return nil
+}
+
+// spkiPinnedTLSConfig builds a tls.Config that accepts a server certificate
+// if and only if its SubjectPublicKeyInfo hash matches one of pins, bypassing
+// the usual CA chain verification (the same trust model as HPKP).
+func spkiPinnedTLSConfig(pins []string, serverName string) *tls.Config {
+	pinSet := make(map[string]bool, len(pins))
+	for _, p := range pins {
+		pinSet[p] = true
+	}
+	return &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			for _, raw := range rawCerts {
+				cert, err := x509.ParseCertificate(raw)
+				if err != nil {
+					continue
+				}
+				sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+				if pinSet[base64.StdEncoding.EncodeToString(sum[:])] {
+					return nil
+				}
+			}
+			return fmt.Errorf("no presented certificate matched a pinned SPKI hash")
+		},
+	}
+}
+
+func withDefaultPort(hostport, port string) string {
+	if !strings.Contains(hostport, ":") {
+		return hostport + ":" + port
+	}
+	return hostport
+}
+
+func bootstrapAddr(u *url.URL, ip string) string {
+	if port := u.Port(); port != "" {
+		return ip + ":" + port
+	}
+	return ip
+}
+
+// exchange runs msg through the configured transports per r.strategy.
+func (r *multiTransportResolver) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, string, string, error) {
+	if len(r.transports) == 0 {
+		return nil, "", "", fmt.Errorf("no transports configured")
+	}
+
+	switch r.strategy {
+	case StrategyParallelRace:
+		return r.exchangeRace(ctx, msg)
+	case StrategyRandom:
+		idx := randomIndex(len(r.transports))
+		t := r.transports[idx]
+		reply, err := t.exchange(ctx, msg)
+		return reply, specLabel(idx), t.name(), err
+	default: // StrategySequential
+		var lastErr error
+		for idx, t := range r.transports {
+			reply, err := t.exchange(ctx, msg)
+			if err == nil {
+				return reply, specLabel(idx), t.name(), nil
+			}
+			lastErr = err
+		}
+		return nil, "", "", fmt.Errorf("all transports failed, last error: %w", lastErr)
+	}
+}
+
+func (r *multiTransportResolver) exchangeRace(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, string, string, error) {
+	type outcome struct {
+		reply     *miekgdns.Msg
+		upstream  string
+		transport string
+		err       error
+	}
+
+	results := make(chan outcome, len(r.transports))
+	for idx, t := range r.transports {
+		idx, t := idx, t
+		go func() {
+			reply, err := t.exchange(ctx, msg)
+			results <- outcome{reply: reply, upstream: specLabel(idx), transport: t.name(), err: err}
+		}()
+	}
+
+	var lastErr error
+	for range r.transports {
+		o := <-results
+		if o.err == nil {
+			return o.reply, o.upstream, o.transport, nil
+		}
+		lastErr = o.err
+	}
+	return nil, "", "", fmt.Errorf("all transports failed, last error: %w", lastErr)
+}
+
+func specLabel(idx int) string {
+	return fmt.Sprintf("upstream[%d]", idx)
+}
+
+// randomIndex avoids a math/rand dependency collision with deterministic
+// test seeding elsewhere in the module; it is intentionally simple.
+func randomIndex(n int) int {
+	return int(time.Now().UnixNano()) % n
+}
+
+// lookupViaTransports performs a lookup using Client.multi, recording which
+// upstream and transport served the answer plus round-trip latency in
+// LookupResult.Metadata.
+func (c *Client) lookupViaTransports(ctx context.Context, domain string, recordType RecordType, result *LookupResult) (*LookupResult, error) {
+	qtype, ok := recordTypeToQtype[recordType]
+	if !ok {
+		err := fmt.Errorf("unsupported record type: %s", recordType)
+		result.Error = err.Error()
+		return result, err
+	}
+
+	msg := new(miekgdns.Msg)
+	msg.SetQuestion(miekgdns.Fqdn(domain), qtype)
+	msg.RecursionDesired = true
+
+	start := time.Now()
+	reply, upstream, transportName, err := c.multi.exchange(ctx, msg)
+	latency := time.Since(start)
+
+	result.Metadata["upstream"] = upstream
+	result.Metadata["transport"] = transportName
+	result.Metadata["latency"] = latency.String()
+
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+
+	if reply.Rcode != miekgdns.RcodeSuccess {
+		err := fmt.Errorf("dns query failed with rcode %s", miekgdns.RcodeToString[reply.Rcode])
+		result.Error = err.Error()
+		return result, negativeErrorFromReply(reply, err)
+	}
+
+	var minTTL time.Duration
+	for i, rr := range reply.Answer {
+		rec := resourceRecordFromRR(rr, recordType)
+		result.Raw = append(result.Raw, rec)
+		result.Records = append(result.Records, rrDataString(rr))
+		if i == 0 || rec.TTL < minTTL {
+			minTTL = rec.TTL
+		}
+	}
+	result.TTL = minTTL
+
+	return result, nil
+}
+
+// udpTransport is plain DNS over UDP/53. A response with the TC (truncated)
+// bit set is automatically retried over tcpFallback, per RFC 1035 4.2.1.
+type udpTransport struct {
+	addr        string
+	timeout     time.Duration
+	tcpFallback *tcpTransport
+}
+
+func (t *udpTransport) name() string { return "udp" }
+
+func (t *udpTransport) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error) {
+	c := &miekgdns.Client{Net: "udp", Timeout: t.timeout}
+	reply, _, err := c.ExchangeContext(ctx, msg, t.addr)
+	if err == nil && reply != nil && reply.Truncated && t.tcpFallback != nil {
+		return t.tcpFallback.exchange(ctx, msg)
+	}
+	return reply, err
+}
+
+// tcpTransport is plain DNS over TCP/53.
+type tcpTransport struct {
+	addr    string
+	timeout time.Duration
+}
+
+func (t *tcpTransport) name() string { return "tcp" }
+
+func (t *tcpTransport) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error) {
+	c := &miekgdns.Client{Net: "tcp", Timeout: t.timeout}
+	reply, _, err := c.ExchangeContext(ctx, msg, t.addr)
+	return reply, err
+}
+
+// dotTransport is DNS-over-TLS (RFC 7858), a TCP-framed query over a TLS
+// connection to port 853 by convention.
+type dotTransport struct {
+	addr       string
+	serverName string
+	tlsConfig  *tls.Config
+	timeout    time.Duration
+}
+
+func (t *dotTransport) name() string { return "dot" }
+
+func (t *dotTransport) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error) {
+	cfg := t.tlsConfig
+	if cfg == nil {
+		cfg = &tls.Config{ServerName: t.serverName}
+	}
+	c := &miekgdns.Client{Net: "tcp-tls", TLSConfig: cfg, Timeout: t.timeout}
+	reply, _, err := c.ExchangeContext(ctx, msg, t.addr)
+	return reply, err
+}
+
+// dohTransport is DNS-over-HTTPS (RFC 8484), POSTing the wire-format query
+// as application/dns-message.
+type dohTransport struct {
+	url       string
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+func (t *dohTransport) name() string { return "doh" }
+
+func (t *dohTransport) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack DoH query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := &http.Client{
+		Timeout:   t.timeout,
+		Transport: &http.Transport{TLSClientConfig: t.tlsConfig},
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("DoH request to %s failed: %w", t.url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH request to %s returned status %d", t.url, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DoH response: %w", err)
+	}
+
+	reply := new(miekgdns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed to unpack DoH response: %w", err)
+	}
+	return reply, nil
+}
+
+// doqTransport is DNS-over-QUIC (RFC 9250). Each query dials its own QUIC
+// connection and sends the message on one bidirectional stream (without
+// the 2-byte TCP length prefix used on DoT/TCP); the connection is closed
+// once the reply is read, so connections are not reused across queries.
+type doqTransport struct {
+	addr       string
+	serverName string
+	tlsConfig  *tls.Config
+	timeout    time.Duration
+}
+
+func (t *doqTransport) name() string { return "doq" }
+
+func (t *doqTransport) exchange(ctx context.Context, msg *miekgdns.Msg) (*miekgdns.Msg, error) {
+	cfg := t.tlsConfig
+	if cfg == nil {
+		cfg = &tls.Config{ServerName: t.serverName, NextProtos: []string{"doq"}}
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	conn, err := quic.DialAddr(dialCtx, t.addr, cfg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("DoQ connection to %s failed: %w", t.addr, err)
+	}
+	defer conn.CloseWithError(0, "")
+
+	stream, err := conn.OpenStreamSync(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("DoQ stream open failed: %w", err)
+	}
+
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack DoQ query: %w", err)
+	}
+	if _, err := stream.Write(packed); err != nil {
+		return nil, fmt.Errorf("failed to write DoQ query: %w", err)
+	}
+	// Close, not CloseWrite: quic-go's Stream has no separate CloseWrite -
+	// Close half-closes the write side (sends a STREAM FIN) without
+	// tearing down the read side, which is what signals the server the
+	// query is complete so it can send its response.
+	if err := stream.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close DoQ stream for writing: %w", err)
+	}
+
+	body, err := io.ReadAll(stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DoQ response: %w", err)
+	}
+
+	reply := new(miekgdns.Msg)
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed to unpack DoQ response: %w", err)
+	}
+	return reply, nil
+}
@@ -0,0 +1,189 @@
+package dns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"io"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	miekgdns "github.com/miekg/dns"
+	"github.com/quic-go/quic-go"
+)
+
+func selfSignedCert(t *testing.T) (der []byte, cert tls.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "dns.example.test"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate: %v", err)
+	}
+	return der, tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func selfSignedCertDER(t *testing.T) []byte {
+	t.Helper()
+	der, _ := selfSignedCert(t)
+	return der
+}
+
+func spkiPin(t *testing.T, der []byte) string {
+	t.Helper()
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("ParseCertificate: %v", err)
+	}
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestNewTransportSchemeDispatch(t *testing.T) {
+	tests := []struct {
+		url      string
+		wantName string
+		wantErr  bool
+	}{
+		{url: "udp://1.1.1.1:53", wantName: "udp"},
+		{url: "tcp://1.1.1.1:53", wantName: "tcp"},
+		{url: "tls://1.1.1.1:853", wantName: "dot"},
+		{url: "https://cloudflare-dns.com/dns-query", wantName: "doh"},
+		{url: "quic://dns.adguard.com:853", wantName: "doq"},
+		{url: "gopher://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			tr, err := newTransport(ResolverSpec{URL: tt.url}, 0)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newTransport(%q) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if tr.name() != tt.wantName {
+				t.Errorf("newTransport(%q).name() = %q, want %q", tt.url, tr.name(), tt.wantName)
+			}
+		})
+	}
+}
+
+func TestSpkiPinnedTLSConfig(t *testing.T) {
+	der := selfSignedCertDER(t)
+	pin := spkiPin(t, der)
+
+	cfg := spkiPinnedTLSConfig([]string{pin}, "dns.example.test")
+	if err := cfg.VerifyPeerCertificate([][]byte{der}, nil); err != nil {
+		t.Errorf("VerifyPeerCertificate() with matching pin = %v, want nil", err)
+	}
+
+	cfg = spkiPinnedTLSConfig([]string{"not-the-real-pin"}, "dns.example.test")
+	if err := cfg.VerifyPeerCertificate([][]byte{der}, nil); err == nil {
+		t.Error("VerifyPeerCertificate() with mismatched pin = nil, want error")
+	}
+}
+
+func TestTlsConfigFor(t *testing.T) {
+	if got := tlsConfigFor(ResolverSpec{}, "host"); got != nil {
+		t.Errorf("tlsConfigFor(empty spec) = %v, want nil", got)
+	}
+	if got := tlsConfigFor(ResolverSpec{PinnedSPKI: []string{"abc"}}, "host"); got == nil {
+		t.Error("tlsConfigFor(spec with PinnedSPKI) = nil, want non-nil")
+	}
+}
+
+// TestDoqTransportExchange drives doqTransport.exchange against a real
+// quic-go listener standing in for a DoQ server, checking that closing the
+// write side with stream.Close() lets the server read the whole query and
+// send back a reply the client can unpack.
+func TestDoqTransportExchange(t *testing.T) {
+	_, cert := selfSignedCert(t)
+
+	ln, err := quic.ListenAddr("127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"doq"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("quic.ListenAddr: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept(context.Background())
+		if err != nil {
+			return
+		}
+		stream, err := conn.AcceptStream(context.Background())
+		if err != nil {
+			return
+		}
+		query, err := io.ReadAll(stream)
+		if err != nil {
+			return
+		}
+		req := new(miekgdns.Msg)
+		if err := req.Unpack(query); err != nil {
+			return
+		}
+		reply := new(miekgdns.Msg)
+		reply.SetReply(req)
+		reply.Answer = append(reply.Answer, &miekgdns.A{
+			Hdr: miekgdns.RR_Header{Name: "example.com.", Rrtype: miekgdns.TypeA, Class: miekgdns.ClassINET, Ttl: 60},
+			A:   net.ParseIP("1.2.3.4"),
+		})
+		packed, err := reply.Pack()
+		if err != nil {
+			return
+		}
+		stream.Write(packed)
+		stream.Close()
+	}()
+
+	tr := &doqTransport{
+		addr:       ln.Addr().String(),
+		serverName: "dns.example.test",
+		tlsConfig:  &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"doq"}},
+		timeout:    5 * time.Second,
+	}
+
+	query := new(miekgdns.Msg)
+	query.SetQuestion("example.com.", miekgdns.TypeA)
+
+	reply, err := tr.exchange(context.Background(), query)
+	if err != nil {
+		t.Fatalf("exchange() error = %v", err)
+	}
+	if len(reply.Answer) != 1 {
+		t.Fatalf("exchange() got %d answers, want 1", len(reply.Answer))
+	}
+	a, ok := reply.Answer[0].(*miekgdns.A)
+	if !ok || a.A.String() != "1.2.3.4" {
+		t.Errorf("exchange() answer = %v, want A 1.2.3.4", reply.Answer[0])
+	}
+}
+
+func TestWithDefaultPort(t *testing.T) {
+	if got := withDefaultPort("1.1.1.1", "53"); got != "1.1.1.1:53" {
+		t.Errorf("withDefaultPort() = %q, want %q", got, "1.1.1.1:53")
+	}
+	if got := withDefaultPort("1.1.1.1:853", "53"); got != "1.1.1.1:853" {
+		t.Errorf("withDefaultPort() = %q, want %q", got, "1.1.1.1:853")
+	}
+}
@@ -0,0 +1,191 @@
+package lookup
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchOptions configures LookupBatch's worker pool, per-WHOIS-server rate
+// limiting, and retry behavior.
+type BatchOptions struct {
+	// Concurrency is how many domains are processed in parallel (default 10).
+	Concurrency int
+	// RatePerServer caps WHOIS queries per second to a single server host,
+	// applied as a token bucket keyed by that host, so a batch run doesn't
+	// trip a registry's own per-minute query cap (e.g. Verisign's). Zero
+	// means unlimited.
+	RatePerServer float64
+	// MaxRetries is how many times a failed lookup is retried with
+	// exponential backoff before giving up (default 2).
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry; it doubles on each
+	// subsequent attempt (default 500ms).
+	RetryBackoff time.Duration
+}
+
+// DefaultBatchOptions returns sane defaults for LookupBatch.
+func DefaultBatchOptions() BatchOptions {
+	return BatchOptions{
+		Concurrency:  10,
+		MaxRetries:   2,
+		RetryBackoff: 500 * time.Millisecond,
+	}
+}
+
+// BatchResult is one domain's outcome from LookupBatch.
+type BatchResult struct {
+	Domain string      `json:"domain"`
+	Info   *DomainInfo `json:"info,omitempty"`
+	Error  string      `json:"error,omitempty"`
+}
+
+// LookupBatch runs LookupAll for every domain in domains concurrently,
+// bounded by opts.Concurrency, rate-limiting WHOIS queries per server host,
+// and retrying failures with exponential backoff. Results stream out of the
+// returned channel as they complete (not necessarily in input order); the
+// channel is closed once every domain has been processed.
+func (c *Client) LookupBatch(ctx context.Context, domains []string, opts BatchOptions) <-chan BatchResult {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 10
+	}
+	if opts.RetryBackoff <= 0 {
+		opts.RetryBackoff = 500 * time.Millisecond
+	}
+
+	results := make(chan BatchResult)
+	limiter := newServerRateLimiter(opts.RatePerServer)
+
+	go func() {
+		defer close(results)
+
+		sem := make(chan struct{}, opts.Concurrency)
+		var wg sync.WaitGroup
+
+		for _, domain := range domains {
+			domain := domain
+
+			select {
+			case <-ctx.Done():
+				results <- BatchResult{Domain: domain, Error: ctx.Err().Error()}
+				continue
+			case sem <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				host := ""
+				if server, err := c.whoisClient.ServerFor(domain); err == nil {
+					host = server.Host
+				}
+
+				info, err := c.lookupAllWithRetry(ctx, domain, opts, func() { limiter.wait(ctx, host) })
+				result := BatchResult{Domain: domain}
+				if err != nil {
+					result.Error = err.Error()
+				} else {
+					result.Info = info
+				}
+				results <- result
+			}()
+		}
+
+		wg.Wait()
+	}()
+
+	return results
+}
+
+// lookupAllWithRetry calls LookupAll, retrying up to opts.MaxRetries times
+// with exponential backoff if it returns an error or a partial failure.
+// throttle is called before every attempt, retries included.
+func (c *Client) lookupAllWithRetry(ctx context.Context, domain string, opts BatchOptions, throttle func()) (*DomainInfo, error) {
+	delay := opts.RetryBackoff
+	var lastErr error
+
+	for attempt := 0; attempt <= opts.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		throttle()
+		info, err := c.LookupAll(ctx, domain)
+		if err == nil && info.Error == "" {
+			return info, nil
+		}
+
+		lastErr = err
+		if lastErr == nil {
+			lastErr = fmt.Errorf("%s", info.Error)
+		}
+	}
+
+	return nil, lastErr
+}
+
+// serverRateLimiter rate-limits queries per server host using a token
+// bucket per host, so that a high-concurrency batch run stays under each
+// registry's own rate limit rather than the batch's overall concurrency.
+type serverRateLimiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens added per second; <= 0 means unlimited
+	buckets map[string]*rateBucket
+}
+
+type rateBucket struct {
+	mu       sync.Mutex
+	tokens   float64
+	lastFill time.Time
+}
+
+func newServerRateLimiter(ratePerSecond float64) *serverRateLimiter {
+	return &serverRateLimiter{rate: ratePerSecond, buckets: make(map[string]*rateBucket)}
+}
+
+// wait blocks until a token is available for host, or ctx is done.
+func (l *serverRateLimiter) wait(ctx context.Context, host string) {
+	if l.rate <= 0 || host == "" {
+		return
+	}
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[host]
+	if !ok {
+		bucket = &rateBucket{tokens: l.rate, lastFill: time.Now()}
+		l.buckets[host] = bucket
+	}
+	l.mu.Unlock()
+
+	bucket.mu.Lock()
+	defer bucket.mu.Unlock()
+
+	for {
+		now := time.Now()
+		bucket.tokens += now.Sub(bucket.lastFill).Seconds() * l.rate
+		if bucket.tokens > l.rate {
+			bucket.tokens = l.rate
+		}
+		bucket.lastFill = now
+
+		if bucket.tokens >= 1 {
+			bucket.tokens--
+			return
+		}
+
+		wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
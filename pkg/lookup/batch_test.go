@@ -0,0 +1,49 @@
+package lookup
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestServerRateLimiterUnlimited(t *testing.T) {
+	limiter := newServerRateLimiter(0)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		limiter.wait(context.Background(), "whois.example.com")
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("wait() with rate<=0 should not block, took %v", elapsed)
+	}
+}
+
+func TestServerRateLimiterAllowsBurstUpToRate(t *testing.T) {
+	limiter := newServerRateLimiter(100) // 100/sec -> ~10ms per token
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		limiter.wait(ctx, "whois.verisign-grs.com")
+	}
+	elapsed := time.Since(start)
+
+	// Burst capacity equals the rate, so 5 tokens from a fresh bucket of 100
+	// should drain immediately rather than block.
+	if elapsed > 50*time.Millisecond {
+		t.Errorf("expected burst of 5 under capacity 100 to not block, took %v", elapsed)
+	}
+}
+
+func TestServerRateLimiterKeyedPerHost(t *testing.T) {
+	limiter := newServerRateLimiter(1) // 1/sec, so a second call on the same host blocks
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	limiter.wait(ctx, "whois.a.test")
+	start := time.Now()
+	limiter.wait(ctx, "whois.b.test") // different host, should not share the bucket
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("different hosts should not share a rate bucket, took %v", elapsed)
+	}
+}
@@ -3,6 +3,7 @@ package lookup
 import (
 	"context"
 	"fmt"
+	"net"
 
 	"github.com/kataras/dns-lookup/pkg/dns"
 	"github.com/kataras/dns-lookup/pkg/whois"
@@ -80,12 +81,68 @@ func (c *Client) DNSLookupSRV(ctx context.Context, service, proto, name string)
 	return c.dnsClient.LookupSRV(ctx, service, proto, name)
 }
 
+// DNSValidate performs a DNSSEC-validating lookup for the specified domain
+// and record type using the underlying DNS client, reporting the
+// Secure/Insecure/Bogus chain-of-trust outcome alongside the normal result.
+func (c *Client) DNSValidate(ctx context.Context, domain string, recordType dns.RecordType) (*dns.LookupResult, error) {
+	return c.dnsClient.Validate(ctx, domain, recordType)
+}
+
+// DNSTrace performs a dig +trace style iterative resolution of domain for
+// the specified record type using the underlying DNS client.
+func (c *Client) DNSTrace(ctx context.Context, domain string, recordType dns.RecordType) ([]dns.TraceStep, error) {
+	return c.dnsClient.Trace(ctx, domain, recordType)
+}
+
+// DNSResolve performs a hostname-to-IP lookup, filtered by the underlying
+// DNS client's Config.QueryStrategy, using the underlying DNS client.
+func (c *Client) DNSResolve(ctx context.Context, host string) ([]net.IP, error) {
+	return c.dnsClient.Resolve(ctx, host)
+}
+
+// DNSLookupAuthoritative queries every nameserver authoritative for domain
+// directly, bypassing the recursive resolver, using the underlying DNS
+// client. It's useful for detecting desync between a domain's authoritative
+// servers.
+func (c *Client) DNSLookupAuthoritative(ctx context.Context, domain string, recordType dns.RecordType) (map[string]*dns.LookupResult, bool, error) {
+	return c.dnsClient.LookupAuthoritative(ctx, domain, recordType)
+}
+
 // WHOISLookup performs a WHOIS lookup for the specified domain
 // using the underlying WHOIS client.
 func (c *Client) WHOISLookup(ctx context.Context, domain string) (*whois.WhoisResult, error) {
 	return c.whoisClient.Lookup(ctx, domain)
 }
 
+// WHOISLookupIP performs a legacy WHOIS lookup for the specified IP address,
+// routed to the authoritative Regional Internet Registry via whois.iana.org,
+// using the underlying WHOIS client.
+func (c *Client) WHOISLookupIP(ctx context.Context, ip net.IP) (*whois.IPWhoisResult, error) {
+	return c.whoisClient.LookupIP(ctx, ip)
+}
+
+// WHOISLookupASN performs a legacy WHOIS lookup for the specified autonomous
+// system number, routed to the authoritative Regional Internet Registry via
+// whois.iana.org, using the underlying WHOIS client.
+func (c *Client) WHOISLookupASN(ctx context.Context, asn uint32) (*whois.IPWhoisResult, error) {
+	return c.whoisClient.LookupASN(ctx, asn)
+}
+
+// WHOISLookupIPRDAP performs an RDAP lookup for the specified IP address
+// using the underlying WHOIS client. It is only meaningful when the client
+// has RDAP available; see WHOISLookupIP for the legacy WHOIS equivalent.
+func (c *Client) WHOISLookupIPRDAP(ctx context.Context, ip string) (*whois.WhoisResult, error) {
+	return c.whoisClient.LookupIPRDAP(ctx, ip)
+}
+
+// WHOISLookupASNRDAP performs an RDAP lookup for the specified autonomous
+// system number using the underlying WHOIS client. It is only meaningful
+// when the client has RDAP available; see WHOISLookupASN for the legacy
+// WHOIS equivalent.
+func (c *Client) WHOISLookupASNRDAP(ctx context.Context, asn string) (*whois.WhoisResult, error) {
+	return c.whoisClient.LookupASNRDAP(ctx, asn)
+}
+
 // DomainInfo contains comprehensive information about a domain including
 // both DNS records and WHOIS data.
 type DomainInfo struct {
@@ -123,6 +180,30 @@ func (c *Client) LookupAll(ctx context.Context, domain string) (*DomainInfo, err
 	return info, nil
 }
 
+// PurgeDNSCache removes every cached DNS entry for domain. It is a no-op if
+// the underlying DNS client's cache is disabled.
+func (c *Client) PurgeDNSCache(domain string) {
+	c.dnsClient.PurgeCache(domain)
+}
+
+// DNSCacheStats returns cumulative hit/miss/eviction counters for the
+// underlying DNS client's result cache.
+func (c *Client) DNSCacheStats() dns.CacheStats {
+	return c.dnsClient.CacheStats()
+}
+
+// PurgeWHOISCache removes the cached WHOIS entry for domain. It is a no-op
+// if the underlying WHOIS client's cache is disabled.
+func (c *Client) PurgeWHOISCache(domain string) {
+	c.whoisClient.PurgeCache(domain)
+}
+
+// WHOISCacheStats returns cumulative hit/miss/eviction counters for the
+// underlying WHOIS client's result cache.
+func (c *Client) WHOISCacheStats() whois.CacheStats {
+	return c.whoisClient.CacheStats()
+}
+
 // DNS returns the underlying DNS client for direct access to DNS operations.
 func (c *Client) DNS() *dns.Client {
 	return c.dnsClient
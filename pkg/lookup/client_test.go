@@ -55,8 +55,34 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+// fakeGoogleResolver returns a FakeResolver registered with enough answers
+// for TestDNSLookup/TestDNSLookupAll to exercise DNSLookup(All) without
+// hitting the network, mirroring the stdlib's resolverdialfunc_test.go
+// approach of swapping in a fake dialer rather than relying on a live DNS
+// server.
+func fakeGoogleResolver() *dns.FakeResolver {
+	fake := dns.NewFakeResolver()
+	fake.Register("google.com", dns.RecordTypeA, dns.FakeAnswer{
+		Records: []dns.ResourceRecord{{Name: "google.com", Type: dns.RecordTypeA, Data: map[string]string{"rdata": "142.250.1.100"}}},
+	})
+	fake.Register("google.com", dns.RecordTypeAAAA, dns.FakeAnswer{
+		Records: []dns.ResourceRecord{{Name: "google.com", Type: dns.RecordTypeAAAA, Data: map[string]string{"rdata": "2607:f8b0::1"}}},
+	})
+	fake.Register("google.com", dns.RecordTypeCNAME, dns.FakeAnswer{Err: dns.ErrNXDomain})
+	fake.Register("google.com", dns.RecordTypeMX, dns.FakeAnswer{
+		Records: []dns.ResourceRecord{{Name: "google.com", Type: dns.RecordTypeMX, Data: map[string]string{"rdata": "smtp.google.com."}}},
+	})
+	fake.Register("google.com", dns.RecordTypeNS, dns.FakeAnswer{
+		Records: []dns.ResourceRecord{{Name: "google.com", Type: dns.RecordTypeNS, Data: map[string]string{"rdata": "ns1.google.com."}}},
+	})
+	fake.Register("google.com", dns.RecordTypeTXT, dns.FakeAnswer{
+		Records: []dns.ResourceRecord{{Name: "google.com", Type: dns.RecordTypeTXT, Data: map[string]string{"rdata": "v=spf1 -all"}}},
+	})
+	return fake
+}
+
 func TestDNSLookup(t *testing.T) {
-	client, err := NewClient(DefaultConfig())
+	client, err := NewClient(&Config{DNS: &dns.Config{Resolver: fakeGoogleResolver()}})
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
@@ -79,7 +105,7 @@ func TestDNSLookup(t *testing.T) {
 }
 
 func TestDNSLookupAll(t *testing.T) {
-	client, err := NewClient(DefaultConfig())
+	client, err := NewClient(&Config{DNS: &dns.Config{Resolver: fakeGoogleResolver()}})
 	if err != nil {
 		t.Fatalf("Failed to create client: %v", err)
 	}
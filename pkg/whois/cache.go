@@ -0,0 +1,134 @@
+package whois
+
+import (
+	"strings"
+	"time"
+
+	"github.com/kataras/dns-lookup/pkg/cache"
+)
+
+// CacheConfig controls the optional in-process result cache on Client.
+// Unlike DNS records, WHOIS responses carry no TTL of their own, so every
+// cached entry uses DefaultTTL rather than one derived from the answer.
+type CacheConfig struct {
+	// Enabled turns the cache on. Disabled by default, matching dns.CacheConfig.
+	Enabled bool
+	// DefaultTTL is how long a cached lookup is served before it's
+	// considered stale. Zero means a reasonable default (24h).
+	DefaultTTL time.Duration
+	// MaxEntries bounds the number of cached domains when the default
+	// MemoryStore is used. Zero means a reasonable default (10000). Has no
+	// effect if Store is set.
+	MaxEntries int
+	// Disable mirrors dns.CacheConfig.Disable: when true the cache is
+	// bypassed even if Enabled is also true.
+	Disable bool
+	// Store overrides the storage backing the cache, e.g. with a Redis- or
+	// BadgerDB-backed cache.Store for a cache shared across processes. Left
+	// nil, an in-process cache.MemoryStore bounded by MaxEntries is used.
+	Store cache.Store
+}
+
+// CacheStats reports cumulative counters for Client's result cache.
+type CacheStats = cache.Stats
+
+// resultCache is a fixed-TTL cache with singleflight request collapsing,
+// keyed by domain. It delegates envelope handling and storage to a
+// cache.TypedCache, so the JSON/singleflight plumbing isn't duplicated per
+// package (see pkg/dns's resultCache for the other instantiation).
+type resultCache struct {
+	store cache.Store
+	typed *cache.TypedCache[WhoisResult]
+	cfg   CacheConfig
+}
+
+func newResultCache(cfg CacheConfig) *resultCache {
+	if cfg.DefaultTTL <= 0 {
+		cfg.DefaultTTL = 24 * time.Hour
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = cache.NewMemoryStore(cfg.MaxEntries, 10*time.Minute)
+	}
+
+	return &resultCache{store: store, typed: cache.NewTypedCache[WhoisResult](store), cfg: cfg}
+}
+
+// lookup serves domain from cache if present and unexpired, otherwise calls
+// fetch (collapsed via singleflight across concurrent callers for the same
+// domain) and stores the outcome. hit reports whether the cache served the
+// answer without calling fetch.
+//
+// A cache hit's Result comes back from a JSON round-trip, so a ParsedData
+// that was empty at put time (as for RDAP results and failed lookups), being
+// tagged `omitempty`, comes back nil rather than empty; ensureParsedData restores the non-nil invariant callers rely on
+// before they write into it.
+func (rc *resultCache) lookup(domain string, fetch func() (*WhoisResult, error)) (result *WhoisResult, err error, hit bool) {
+	if rc.cfg.Disable || !rc.cfg.Enabled {
+		result, err = fetch()
+		return result, err, false
+	}
+
+	if result, err, ok := rc.typed.Get(domain); ok {
+		ensureParsedData(result)
+		return result, err, true
+	}
+
+	result, err = rc.typed.Do(domain, func() (*WhoisResult, error) {
+		res, ferr := fetch()
+		rc.put(domain, res, ferr)
+		return res, ferr
+	})
+	return result, err, false
+}
+
+// ensureParsedData makes result.ParsedData non-nil if result is non-nil, so
+// callers can unconditionally write into it after a cache hit.
+func ensureParsedData(result *WhoisResult) {
+	if result != nil && result.ParsedData == nil {
+		result.ParsedData = make(map[string]interface{})
+	}
+}
+
+func (rc *resultCache) put(domain string, result *WhoisResult, err error) {
+	rc.typed.Put(domain, result, err, rc.cfg.DefaultTTL)
+}
+
+func (rc *resultCache) purge(domain string) {
+	rc.store.Delete(domain)
+}
+
+// statsStore is implemented by cache.Store backends that can cheaply report
+// Stats; cache.MemoryStore does, a remote store might not.
+type statsStore interface {
+	Stats() cache.Stats
+}
+
+func (rc *resultCache) stats() CacheStats {
+	if ss, ok := rc.store.(statsStore); ok {
+		return ss.Stats()
+	}
+	return CacheStats{}
+}
+
+// PurgeCache removes the cached entry for domain, if any. It is a no-op if
+// the cache is disabled.
+func (c *Client) PurgeCache(domain string) {
+	if c.cache == nil {
+		return
+	}
+	c.cache.purge(strings.ToLower(strings.TrimSpace(domain)))
+}
+
+// CacheStats returns cumulative hit/miss/eviction counters for the result
+// cache. Returns a zero value if caching is disabled.
+func (c *Client) CacheStats() CacheStats {
+	if c.cache == nil {
+		return CacheStats{}
+	}
+	return c.cache.stats()
+}
@@ -0,0 +1,60 @@
+package whois
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResultCacheHitMiss(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true, DefaultTTL: time.Minute})
+
+	calls := 0
+	fetch := func() (*WhoisResult, error) {
+		calls++
+		return &WhoisResult{Domain: "example.com", Registrar: "Example Registrar"}, nil
+	}
+
+	if _, _, hit := rc.lookup("example.com", fetch); hit {
+		t.Error("expected first lookup to miss")
+	}
+	if _, _, hit := rc.lookup("example.com", fetch); !hit {
+		t.Error("expected second lookup to hit")
+	}
+	if calls != 1 {
+		t.Errorf("expected fetch to run once, ran %d times", calls)
+	}
+}
+
+func TestResultCacheCachesErrors(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true, DefaultTTL: time.Minute})
+
+	calls := 0
+	fetch := func() (*WhoisResult, error) {
+		calls++
+		return &WhoisResult{Domain: "broken.example"}, errors.New("connection refused")
+	}
+
+	rc.lookup("broken.example", fetch)
+	_, err, hit := rc.lookup("broken.example", fetch)
+	if !hit {
+		t.Error("expected errored result to be cached")
+	}
+	if err == nil || err.Error() != "connection refused" {
+		t.Errorf("err = %v, want \"connection refused\"", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected fetch to run once, ran %d times", calls)
+	}
+}
+
+func TestResultCachePurge(t *testing.T) {
+	rc := newResultCache(CacheConfig{Enabled: true})
+	rc.put("example.com", &WhoisResult{Domain: "example.com"}, nil)
+
+	rc.purge("example.com")
+
+	if _, _, hit := rc.lookup("example.com", func() (*WhoisResult, error) { return nil, nil }); hit {
+		t.Error("expected purged entry to miss")
+	}
+}
@@ -0,0 +1,140 @@
+package whois
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ianaWhoisServer is always queried first for an IP or ASN lookup, mirroring
+// the role getWhoisServer plays for domain TLDs: IANA doesn't hold
+// registration data itself, only a referral to whichever Regional Internet
+// Registry is authoritative for the address range or AS number.
+var ianaWhoisServer = WhoisServer{Host: "whois.iana.org", Port: "43"}
+
+// IPWhoisResult contains the result of a legacy WHOIS query for an IP
+// address or autonomous system number, after following the IANA referral to
+// the authoritative Regional Internet Registry.
+type IPWhoisResult struct {
+	Query        string    `json:"query"`
+	Server       string    `json:"server"`
+	RawResponse  string    `json:"raw_response"`
+	Timestamp    time.Time `json:"timestamp"`
+	Error        string    `json:"error,omitempty"`
+	CIDR         string    `json:"cidr,omitempty"`
+	NetName      string    `json:"net_name,omitempty"`
+	Origin       string    `json:"origin,omitempty"`
+	OrgName      string    `json:"org_name,omitempty"`
+	Country      string    `json:"country,omitempty"`
+	AbuseContact string    `json:"abuse_contact,omitempty"`
+}
+
+// LookupIP performs a legacy WHOIS lookup for ip: it queries whois.iana.org
+// to find the authoritative Regional Internet Registry (ARIN, RIPE, APNIC,
+// LACNIC, or AFRINIC), then re-queries that RIR with its own query syntax.
+// See LookupIPRDAP for the RDAP equivalent.
+func (c *Client) LookupIP(ctx context.Context, ip net.IP) (*IPWhoisResult, error) {
+	return c.lookupRIR(ctx, ip.String())
+}
+
+// LookupASN performs a legacy WHOIS lookup for an autonomous system number,
+// routed to the authoritative RIR the same way LookupIP routes an address.
+// See LookupASNRDAP for the RDAP equivalent.
+func (c *Client) LookupASN(ctx context.Context, asn uint32) (*IPWhoisResult, error) {
+	return c.lookupRIR(ctx, fmt.Sprintf("AS%d", asn))
+}
+
+// lookupRIR queries whois.iana.org for query (an IP address or "AS<number>"
+// string), follows its refer: referral to the authoritative RIR, and
+// parses the RIR's response into an IPWhoisResult.
+func (c *Client) lookupRIR(ctx context.Context, query string) (*IPWhoisResult, error) {
+	if ctx == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
+		defer cancel()
+	}
+
+	result := &IPWhoisResult{Query: query, Timestamp: time.Now()}
+
+	response, err := c.query(ctx, query, ianaWhoisServer)
+	if err != nil {
+		result.Error = err.Error()
+		return result, err
+	}
+	result.RawResponse = response
+	result.Server = fmt.Sprintf("%s:%s", ianaWhoisServer.Host, ianaWhoisServer.Port)
+
+	if rirHost := c.extractReferralServer(response); rirHost != "" {
+		rir := WhoisServer{Host: rirHost, Port: "43"}
+		if rirResponse, err := c.query(ctx, rirQuery(rir, query), rir); err == nil {
+			result.RawResponse = rirResponse
+			result.Server = fmt.Sprintf("%s:%s", rir.Host, rir.Port)
+		}
+	}
+
+	parseIPWhoisResponse(result.RawResponse, result)
+	return result, nil
+}
+
+// rirQuery adapts query to the flagged syntax a specific RIR's WHOIS server
+// expects instead of a bare address/AS number: RIPE wants "-B <query>" to
+// suppress personal data in the response, and ARIN distinguishes network
+// lookups ("a <ip>") from exact AS-handle lookups ("n + AS<number>"). APNIC,
+// LACNIC, and AFRINIC all accept the bare query as-is.
+func rirQuery(server WhoisServer, query string) string {
+	switch server.Host {
+	case "whois.ripe.net":
+		return "-B " + query
+	case "whois.arin.net":
+		if strings.HasPrefix(query, "AS") {
+			return "n + " + query
+		}
+		return "a " + query
+	default:
+		return query
+	}
+}
+
+// parseIPWhoisResponse extracts the common fields RIRs report (under
+// somewhat different names) into result.
+func parseIPWhoisResponse(raw string, result *IPWhoisResult) {
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case "cidr", "inetnum", "inet6num", "netrange":
+			if result.CIDR == "" {
+				result.CIDR = value
+			}
+		case "netname":
+			result.NetName = value
+		case "origin", "originas":
+			result.Origin = value
+		case "org-name", "orgname", "owner", "descr":
+			if result.OrgName == "" {
+				result.OrgName = value
+			}
+		case "country":
+			result.Country = value
+		case "abuse-mailbox", "orgabuseemail":
+			if result.AbuseContact == "" {
+				result.AbuseContact = value
+			}
+		}
+	}
+}
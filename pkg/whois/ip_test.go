@@ -0,0 +1,54 @@
+package whois
+
+import "testing"
+
+func TestRirQuery(t *testing.T) {
+	tests := []struct {
+		server WhoisServer
+		query  string
+		want   string
+	}{
+		{WhoisServer{Host: "whois.ripe.net"}, "192.0.2.1", "-B 192.0.2.1"},
+		{WhoisServer{Host: "whois.arin.net"}, "192.0.2.1", "a 192.0.2.1"},
+		{WhoisServer{Host: "whois.arin.net"}, "AS64512", "n + AS64512"},
+		{WhoisServer{Host: "whois.apnic.net"}, "192.0.2.1", "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		if got := rirQuery(tt.server, tt.query); got != tt.want {
+			t.Errorf("rirQuery(%q, %q) = %q, want %q", tt.server.Host, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseIPWhoisResponse(t *testing.T) {
+	raw := `% This is a comment
+inetnum:        192.0.2.0 - 192.0.2.255
+netname:        EXAMPLE-NET
+country:        NL
+org-name:       Example Org
+origin:         AS64512
+abuse-mailbox:  abuse@example-net.test
+`
+	result := &IPWhoisResult{}
+	parseIPWhoisResponse(raw, result)
+
+	if result.CIDR != "192.0.2.0 - 192.0.2.255" {
+		t.Errorf("CIDR = %q", result.CIDR)
+	}
+	if result.NetName != "EXAMPLE-NET" {
+		t.Errorf("NetName = %q", result.NetName)
+	}
+	if result.Origin != "AS64512" {
+		t.Errorf("Origin = %q", result.Origin)
+	}
+	if result.OrgName != "Example Org" {
+		t.Errorf("OrgName = %q", result.OrgName)
+	}
+	if result.Country != "NL" {
+		t.Errorf("Country = %q", result.Country)
+	}
+	if result.AbuseContact != "abuse@example-net.test" {
+		t.Errorf("AbuseContact = %q", result.AbuseContact)
+	}
+}
@@ -0,0 +1,485 @@
+package whois
+
+import "strings"
+
+// Parser extracts structured fields from a raw WHOIS response into a
+// WhoisResult. Match reports whether this Parser knows how to handle
+// responses from server (the WHOIS host that was actually queried,
+// including after a referral) or tld (the domain's top-level label);
+// ParserRegistry picks the first Parser whose Match returns true.
+type Parser interface {
+	Match(server, tld string) bool
+	Parse(raw string, out *WhoisResult) error
+}
+
+// ParserRegistry selects a Parser by the server that answered a query (or
+// the domain's TLD), falling back to a generic colon/key-value parser for
+// registries without a dedicated one.
+type ParserRegistry struct {
+	parsers  []Parser
+	fallback Parser
+}
+
+// NewParserRegistry returns a registry pre-loaded with the built-in
+// per-registrar parsers, falling back to the generic parser when none
+// match.
+func NewParserRegistry() *ParserRegistry {
+	return &ParserRegistry{
+		parsers: []Parser{
+			verisignParser{},
+			pirParser{},
+			nominetParser{},
+			denicParser{},
+			jprsParser{},
+			afnicParser{},
+			ruCenterParser{},
+			sidnParser{},
+		},
+		fallback: genericParser{},
+	}
+}
+
+// ParserFor returns the Parser that should handle a response from server
+// for a domain whose TLD is tld.
+func (r *ParserRegistry) ParserFor(server, tld string) Parser {
+	for _, p := range r.parsers {
+		if p.Match(server, tld) {
+			return p
+		}
+	}
+	return r.fallback
+}
+
+// matchAny reports whether host contains any of needles, case-insensitively.
+func matchAny(host string, needles ...string) bool {
+	host = strings.ToLower(host)
+	for _, n := range needles {
+		if strings.Contains(host, n) {
+			return true
+		}
+	}
+	return false
+}
+
+// genericParser is the original colon/key-value scanner: it recognizes a
+// handful of common field name spellings regardless of registry, which
+// works reasonably well for registries that don't have a dedicated parser.
+type genericParser struct{}
+
+func (genericParser) Match(server, tld string) bool { return true }
+
+func (genericParser) Parse(raw string, result *WhoisResult) error {
+	lines := strings.Split(raw, "\n")
+
+	var nameServers []string
+	var status []string
+	var emails []string
+
+	emailMap := make(map[string]bool)
+	nsMap := make(map[string]bool)
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lower := strings.ToLower(line)
+
+		if result.Registrar == "" && (strings.Contains(lower, "registrar:") || strings.Contains(lower, "registrar name:")) {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				result.Registrar = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if result.CreatedDate == "" && (strings.Contains(lower, "creation date:") || strings.Contains(lower, "created:") || strings.Contains(lower, "registered:")) {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				result.CreatedDate = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if result.ExpiryDate == "" && (strings.Contains(lower, "expiry date:") || strings.Contains(lower, "expiration date:") || strings.Contains(lower, "expires:")) {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				result.ExpiryDate = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if result.UpdatedDate == "" && (strings.Contains(lower, "updated date:") || strings.Contains(lower, "last updated:") || strings.Contains(lower, "modified:")) {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				result.UpdatedDate = strings.TrimSpace(parts[1])
+			}
+		}
+
+		if strings.Contains(lower, "name server:") || strings.Contains(lower, "nserver:") || strings.Contains(lower, "nameserver:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				ns := strings.TrimSpace(parts[1])
+				ns = strings.ToLower(ns)
+				if idx := strings.Index(ns, " "); idx > 0 {
+					ns = ns[:idx]
+				}
+				if !nsMap[ns] {
+					nameServers = append(nameServers, ns)
+					nsMap[ns] = true
+				}
+			}
+		}
+
+		if strings.Contains(lower, "status:") || strings.Contains(lower, "domain status:") {
+			parts := strings.SplitN(line, ":", 2)
+			if len(parts) == 2 {
+				statusValue := strings.TrimSpace(parts[1])
+				if statusValue != "" {
+					status = append(status, statusValue)
+				}
+			}
+		}
+
+		if strings.Contains(line, "@") {
+			words := strings.Fields(line)
+			for _, word := range words {
+				if strings.Contains(word, "@") && strings.Contains(word, ".") {
+					email := strings.Trim(word, ",:;()<>[]")
+					if !emailMap[email] && isValidEmail(email) {
+						emails = append(emails, email)
+						emailMap[email] = true
+					}
+				}
+			}
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.Emails = emails
+
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["created_date"] = result.CreatedDate
+	result.ParsedData["expiry_date"] = result.ExpiryDate
+	result.ParsedData["updated_date"] = result.UpdatedDate
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	result.ParsedData["emails"] = result.Emails
+
+	return nil
+}
+
+// verisignParser handles .com/.net, whose WHOIS output the genericParser's
+// field names were originally modeled on; it exists mainly so the registry
+// routes these TLDs explicitly instead of falling through to the fallback.
+type verisignParser struct{ genericParser }
+
+func (verisignParser) Match(server, tld string) bool {
+	return matchAny(server, "verisign-grs.com") || tld == "com" || tld == "net"
+}
+
+// pirParser handles .org, whose Public Interest Registry WHOIS output also
+// follows the same generic key/value layout.
+type pirParser struct{ genericParser }
+
+func (pirParser) Match(server, tld string) bool {
+	return matchAny(server, "pir.org") || tld == "org"
+}
+
+// nominetParser handles .uk, whose Nominet WHOIS groups dates and
+// nameservers under indented multi-line headers rather than one
+// "field: value" per line.
+type nominetParser struct{}
+
+func (nominetParser) Match(server, tld string) bool {
+	return matchAny(server, "nic.uk") || tld == "uk"
+}
+
+func (nominetParser) Parse(raw string, result *WhoisResult) error {
+	lines := strings.Split(raw, "\n")
+
+	var nameServers []string
+	var status []string
+	section := ""
+
+	for _, rawLine := range lines {
+		line := strings.TrimRight(rawLine, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		lower := strings.ToLower(trimmed)
+		indented := line != trimmed
+
+		switch {
+		case lower == "registrar:":
+			section = "registrar"
+		case lower == "relevant dates:":
+			section = "dates"
+		case lower == "name servers:":
+			section = "nameservers"
+		case lower == "registration status:":
+			section = "status"
+		case !indented:
+			section = ""
+		case section == "registrar":
+			if result.Registrar == "" && !strings.HasPrefix(lower, "url:") {
+				result.Registrar = trimmed
+			}
+		case section == "dates":
+			if strings.HasPrefix(lower, "registered on:") {
+				result.CreatedDate = strings.TrimSpace(trimmed[len("registered on:"):])
+			} else if strings.HasPrefix(lower, "renewal date:") {
+				result.ExpiryDate = strings.TrimSpace(trimmed[len("renewal date:"):])
+			} else if strings.HasPrefix(lower, "last updated:") {
+				result.UpdatedDate = strings.TrimSpace(trimmed[len("last updated:"):])
+			}
+		case section == "nameservers":
+			nameServers = append(nameServers, strings.ToLower(strings.Fields(trimmed)[0]))
+		case section == "status":
+			status = append(status, trimmed)
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
+
+// denicParser handles .de, whose DENIC WHOIS omits registrar/registrant
+// details for privacy and reports only "Nserver:", "Status:" and "Changed:"
+// lines.
+type denicParser struct{}
+
+func (denicParser) Match(server, tld string) bool {
+	return matchAny(server, "denic.de") || tld == "de"
+}
+
+func (denicParser) Parse(raw string, result *WhoisResult) error {
+	var nameServers []string
+	var status []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "nserver":
+			nameServers = append(nameServers, strings.ToLower(strings.Fields(value)[0]))
+		case "status":
+			status = append(status, value)
+		case "changed":
+			result.UpdatedDate = value
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
+
+// jprsParser handles .jp, whose JPRS WHOIS uses bracketed field labels
+// ("[Domain Name]", "[Registrant]", ...) instead of "field: value" lines.
+type jprsParser struct{}
+
+func (jprsParser) Match(server, tld string) bool {
+	return matchAny(server, "jprs.jp") || tld == "jp"
+}
+
+func (jprsParser) Parse(raw string, result *WhoisResult) error {
+	var nameServers []string
+	var status []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "[") {
+			continue
+		}
+		end := strings.Index(line, "]")
+		if end < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[1:end]))
+		value := strings.TrimSpace(line[end+1:])
+		if value == "" {
+			continue
+		}
+
+		switch key {
+		case "registrant":
+			result.Registrar = value
+		case "name server":
+			nameServers = append(nameServers, strings.ToLower(value))
+		case "status":
+			status = append(status, value)
+		case "created on":
+			result.CreatedDate = value
+		case "expires on":
+			result.ExpiryDate = value
+		case "last updated":
+			result.UpdatedDate = value
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
+
+// afnicParser handles .fr, whose AFNIC WHOIS uses lowercase
+// "field:    value" lines with AFNIC-specific field names
+// (hold/created/last-update/expiration-date/nserver).
+type afnicParser struct{}
+
+func (afnicParser) Match(server, tld string) bool {
+	return matchAny(server, "nic.fr", "afnic.fr") || tld == "fr"
+}
+
+func (afnicParser) Parse(raw string, result *WhoisResult) error {
+	var nameServers []string
+	var status []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "registrar":
+			result.Registrar = value
+		case "status":
+			status = append(status, value)
+		case "created":
+			result.CreatedDate = value
+		case "last-update":
+			result.UpdatedDate = value
+		case "expiration-date":
+			result.ExpiryDate = value
+		case "nserver":
+			nameServers = append(nameServers, strings.ToLower(strings.Fields(value)[0]))
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
+
+// ruCenterParser handles .ru/.su, whose RU-CENTER WHOIS reports lifecycle
+// state via "state:" rather than "status:" and expiry via "paid-till:"
+// rather than "expiry date:".
+type ruCenterParser struct{}
+
+func (ruCenterParser) Match(server, tld string) bool {
+	return matchAny(server, "tcinet.ru") || tld == "ru" || tld == "su"
+}
+
+func (ruCenterParser) Parse(raw string, result *WhoisResult) error {
+	var nameServers []string
+	var status []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "%") {
+			continue
+		}
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+
+		switch key {
+		case "registrar":
+			result.Registrar = value
+		case "state":
+			status = append(status, value)
+		case "created":
+			result.CreatedDate = value
+		case "paid-till":
+			result.ExpiryDate = value
+		case "nserver":
+			ns := strings.ToLower(strings.Fields(value)[0])
+			nameServers = append(nameServers, strings.TrimSuffix(ns, "."))
+		}
+	}
+
+	result.NameServers = nameServers
+	result.Status = status
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
+
+// sidnParser handles .nl, whose SIDN WHOIS puts the registrar name and
+// nameservers on the lines following a bare "Registrar:" / "Domain
+// nameservers:" header rather than on the header line itself.
+type sidnParser struct{}
+
+func (sidnParser) Match(server, tld string) bool {
+	return matchAny(server, "domain-registry.nl", "sidn.nl") || tld == "nl"
+}
+
+func (sidnParser) Parse(raw string, result *WhoisResult) error {
+	var nameServers []string
+	section := ""
+
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			section = ""
+			continue
+		}
+
+		lower := strings.ToLower(trimmed)
+		switch {
+		case strings.HasPrefix(lower, "domain name:"):
+			section = ""
+		case strings.HasPrefix(lower, "status:"):
+			result.Status = append(result.Status, strings.TrimSpace(trimmed[len("status:"):]))
+			section = ""
+		case lower == "registrar:":
+			section = "registrar"
+		case lower == "domain nameservers:":
+			section = "nameservers"
+		case section == "registrar" && result.Registrar == "":
+			result.Registrar = trimmed
+		case section == "nameservers":
+			nameServers = append(nameServers, strings.ToLower(strings.Fields(trimmed)[0]))
+		}
+	}
+
+	result.NameServers = nameServers
+	result.ParsedData["registrar"] = result.Registrar
+	result.ParsedData["name_servers"] = result.NameServers
+	result.ParsedData["status"] = result.Status
+	return nil
+}
@@ -0,0 +1,193 @@
+package whois
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParserRegistry(t *testing.T) {
+	tests := []struct {
+		name        string
+		fixture     string
+		server      string
+		tld         string
+		wantParser  Parser
+		registrar   string
+		createdDate string
+		expiryDate  string
+		updatedDate string
+		nameServers []string
+		status      []string
+	}{
+		{
+			name:        "com uses verisign parser",
+			fixture:     "com.txt",
+			server:      "whois.verisign-grs.com",
+			tld:         "com",
+			wantParser:  verisignParser{},
+			registrar:   "Example Registrar Inc.",
+			createdDate: "2020-01-01T00:00:00Z",
+			expiryDate:  "2025-01-01T00:00:00Z",
+			updatedDate: "2024-01-01T00:00:00Z",
+			nameServers: []string{"ns1.example.com", "ns2.example.com"},
+			status:      []string{"clientTransferProhibited"},
+		},
+		{
+			name:        "uk uses nominet parser",
+			fixture:     "uk.txt",
+			server:      "whois.nic.uk",
+			tld:         "uk",
+			wantParser:  nominetParser{},
+			registrar:   "Example Registrar Ltd t/a Example [Tag = EXAMPLE]",
+			createdDate: "08-Aug-1996",
+			expiryDate:  "08-Aug-2025",
+			updatedDate: "13-Jan-2024",
+			nameServers: []string{"ns1.example.co.uk", "ns2.example.co.uk"},
+			status:      []string{"Registered until renewal date."},
+		},
+		{
+			name:        "de uses denic parser",
+			fixture:     "de.txt",
+			server:      "whois.denic.de",
+			tld:         "de",
+			wantParser:  denicParser{},
+			updatedDate: "2024-01-01T00:00:00+01:00",
+			nameServers: []string{"ns1.example.de", "ns2.example.de"},
+			status:      []string{"connect"},
+		},
+		{
+			name:        "jp uses jprs parser",
+			fixture:     "jp.txt",
+			server:      "whois.jprs.jp",
+			tld:         "jp",
+			wantParser:  jprsParser{},
+			registrar:   "Example Registrant Co., Ltd.",
+			createdDate: "2000/01/01",
+			expiryDate:  "2025/01/01",
+			updatedDate: "2024/01/01",
+			nameServers: []string{"ns1.example.jp", "ns2.example.jp"},
+			status:      []string{"Active"},
+		},
+		{
+			name:        "fr uses afnic parser",
+			fixture:     "fr.txt",
+			server:      "whois.nic.fr",
+			tld:         "fr",
+			wantParser:  afnicParser{},
+			registrar:   "EXAMPLE REGISTRAR",
+			createdDate: "2000-01-01",
+			expiryDate:  "2025-01-01",
+			updatedDate: "2024-01-01",
+			nameServers: []string{"ns1.example.fr", "ns2.example.fr"},
+			status:      []string{"ACTIVE"},
+		},
+		{
+			name:        "ru uses ru-center parser",
+			fixture:     "ru.txt",
+			server:      "whois.tcinet.ru",
+			tld:         "ru",
+			wantParser:  ruCenterParser{},
+			registrar:   "RU-CENTER-RU",
+			createdDate: "2000-01-01T00:00:00Z",
+			expiryDate:  "2025-01-01T00:00:00Z",
+			nameServers: []string{"ns1.example.ru", "ns2.example.ru"},
+			status:      []string{"REGISTERED, DELEGATED, VERIFIED"},
+		},
+		{
+			name:        "nl uses sidn parser",
+			fixture:     "nl.txt",
+			server:      "whois.domain-registry.nl",
+			tld:         "nl",
+			wantParser:  sidnParser{},
+			registrar:   "Example Registrar B.V.",
+			nameServers: []string{"ns1.example.nl", "ns2.example.nl"},
+			status:      []string{"active"},
+		},
+	}
+
+	registry := NewParserRegistry()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := os.ReadFile(filepath.Join("testdata", tt.fixture))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			parser := registry.ParserFor(tt.server, tt.tld)
+			if got, want := parserTypeName(parser), parserTypeName(tt.wantParser); got != want {
+				t.Fatalf("ParserFor(%q, %q) = %s, want %s", tt.server, tt.tld, got, want)
+			}
+
+			result := &WhoisResult{Domain: "example." + tt.tld, ParsedData: make(map[string]interface{})}
+			if err := parser.Parse(string(raw), result); err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if result.Registrar != tt.registrar {
+				t.Errorf("Registrar = %q, want %q", result.Registrar, tt.registrar)
+			}
+			if result.CreatedDate != tt.createdDate {
+				t.Errorf("CreatedDate = %q, want %q", result.CreatedDate, tt.createdDate)
+			}
+			if result.ExpiryDate != tt.expiryDate {
+				t.Errorf("ExpiryDate = %q, want %q", result.ExpiryDate, tt.expiryDate)
+			}
+			if result.UpdatedDate != tt.updatedDate {
+				t.Errorf("UpdatedDate = %q, want %q", result.UpdatedDate, tt.updatedDate)
+			}
+			if !equalStrings(result.NameServers, tt.nameServers) {
+				t.Errorf("NameServers = %v, want %v", result.NameServers, tt.nameServers)
+			}
+			if !equalStrings(result.Status, tt.status) {
+				t.Errorf("Status = %v, want %v", result.Status, tt.status)
+			}
+		})
+	}
+}
+
+func TestParserRegistryFallsBackToGeneric(t *testing.T) {
+	registry := NewParserRegistry()
+	parser := registry.ParserFor("whois.example-registry.test", "test")
+	if parserTypeName(parser) != parserTypeName(genericParser{}) {
+		t.Errorf("expected generic parser fallback for an unknown TLD, got %s", parserTypeName(parser))
+	}
+}
+
+func parserTypeName(p Parser) string {
+	switch p.(type) {
+	case verisignParser:
+		return "verisignParser"
+	case pirParser:
+		return "pirParser"
+	case nominetParser:
+		return "nominetParser"
+	case denicParser:
+		return "denicParser"
+	case jprsParser:
+		return "jprsParser"
+	case afnicParser:
+		return "afnicParser"
+	case ruCenterParser:
+		return "ruCenterParser"
+	case sidnParser:
+		return "sidnParser"
+	case genericParser:
+		return "genericParser"
+	default:
+		return "unknown"
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
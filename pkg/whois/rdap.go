@@ -0,0 +1,587 @@
+package whois
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoRDAPService is returned when a domain's TLD has no RDAP service
+// registered in the IANA bootstrap registry, or when the authoritative RDAP
+// server has no record for the queried name (HTTP 404). Either case tells
+// the caller to fall back to legacy WHOIS.
+var ErrNoRDAPService = errors.New("rdap: no RDAP service for this domain")
+
+// rdapBootstrapTTL is how long the IANA RDAP bootstrap registry is cached
+// before being re-fetched.
+const rdapBootstrapTTL = 24 * time.Hour
+
+// ianaBootstrapDNS is the IANA RDAP bootstrap registry for domain names
+// (RFC 9224).
+const ianaBootstrapDNS = "https://data.iana.org/rdap/dns.json"
+
+// IANA RDAP bootstrap registries for IP networks and autonomous system
+// numbers (RFC 9224), used by RDAPClient.LookupIP and LookupASN.
+const (
+	ianaBootstrapIPv4 = "https://data.iana.org/rdap/ipv4.json"
+	ianaBootstrapIPv6 = "https://data.iana.org/rdap/ipv6.json"
+	ianaBootstrapASN  = "https://data.iana.org/rdap/asn.json"
+)
+
+// bootstrapRegistry mirrors the IANA RDAP bootstrap JSON format (RFC 9224):
+// "services" is a list of [tlds, baseURLs] pairs.
+type bootstrapRegistry struct {
+	Services [][][]string `json:"services"`
+}
+
+// bootstrapCache caches a fetched bootstrapRegistry for rdapBootstrapTTL so
+// repeated lookups don't re-fetch it every time.
+type bootstrapCache struct {
+	mu        sync.Mutex
+	fetchedAt time.Time
+	registry  *bootstrapRegistry
+}
+
+func (b *bootstrapCache) get(ctx context.Context, client *http.Client, url string) (*bootstrapRegistry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.registry != nil && time.Since(b.fetchedAt) < rdapBootstrapTTL {
+		return b.registry, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rdap: bootstrap fetch from %s returned status %d", url, resp.StatusCode)
+	}
+
+	var registry bootstrapRegistry
+	if err := json.NewDecoder(resp.Body).Decode(&registry); err != nil {
+		return nil, fmt.Errorf("rdap: failed to decode bootstrap from %s: %w", url, err)
+	}
+
+	b.registry = &registry
+	b.fetchedAt = time.Now()
+	return b.registry, nil
+}
+
+// rdapLink mirrors an RDAP "links" entry (RFC 9083 section 4.2).
+type rdapLink struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// rdapEvent mirrors an RDAP "events" entry (RFC 9083 section 4.5).
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+// rdapNameserver mirrors an RDAP domain's "nameservers" entry.
+type rdapNameserver struct {
+	LDHName string `json:"ldhName"`
+}
+
+// rdapEntity mirrors an RDAP "entities" entry; Name/Emails are decoded from
+// the jCard VCardArray on demand via entityName/entityEmails.
+type rdapEntity struct {
+	Roles      []string      `json:"roles"`
+	VCardArray []interface{} `json:"vcardArray"`
+}
+
+// rdapDomainResponse mirrors the subset of an RDAP domain object (RFC 9083
+// section 5) this client maps onto WhoisResult.
+type rdapDomainResponse struct {
+	LDHName     string           `json:"ldhName"`
+	Status      []string         `json:"status"`
+	Events      []rdapEvent      `json:"events"`
+	Nameservers []rdapNameserver `json:"nameservers"`
+	Entities    []rdapEntity     `json:"entities"`
+	Links       []rdapLink       `json:"links"`
+}
+
+// RDAPClient performs RDAP lookups (RFC 7480/7482/9082/9083) for domains. It
+// selects the authoritative RDAP base URL via the IANA bootstrap registry,
+// so it has no notion of per-TLD servers the way the legacy WHOIS Client
+// does.
+type RDAPClient struct {
+	httpClient    *http.Client
+	dnsBootstrap  *bootstrapCache
+	ipv4Bootstrap *bootstrapCache
+	ipv6Bootstrap *bootstrapCache
+	asnBootstrap  *bootstrapCache
+}
+
+// NewRDAPClient creates an RDAP client with the given request timeout.
+func NewRDAPClient(timeout time.Duration) *RDAPClient {
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &RDAPClient{
+		httpClient:    &http.Client{Timeout: timeout},
+		dnsBootstrap:  &bootstrapCache{},
+		ipv4Bootstrap: &bootstrapCache{},
+		ipv6Bootstrap: &bootstrapCache{},
+		asnBootstrap:  &bootstrapCache{},
+	}
+}
+
+// LookupDomain performs an RDAP domain lookup, following one level of
+// rel=related referral (typically to the registrar's own RDAP service),
+// analogous to the legacy WHOIS client's referral chasing. It returns
+// ErrNoRDAPService if the TLD has no RDAP service or the server reports the
+// domain as not found.
+func (c *RDAPClient) LookupDomain(ctx context.Context, domain string) (*WhoisResult, error) {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+
+	baseURLs, err := c.baseURLsForDomain(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, base := range baseURLs {
+		result, err := c.fetchDomain(ctx, base, domain)
+		if err == nil {
+			return result, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// baseURLsForDomain returns the RDAP base URLs registered for domain's TLD.
+func (c *RDAPClient) baseURLsForDomain(ctx context.Context, domain string) ([]string, error) {
+	registry, err := c.dnsBootstrap.get(ctx, c.httpClient, ianaBootstrapDNS)
+	if err != nil {
+		return nil, err
+	}
+
+	tld := lastLabel(domain)
+	for _, svc := range registry.Services {
+		if len(svc) != 2 {
+			continue
+		}
+		for _, t := range svc[0] {
+			if strings.EqualFold(t, tld) {
+				return svc[1], nil
+			}
+		}
+	}
+	return nil, ErrNoRDAPService
+}
+
+func (c *RDAPClient) fetchDomain(ctx context.Context, baseURL, domain string) (*WhoisResult, error) {
+	url := strings.TrimSuffix(baseURL, "/") + "/domain/" + domain
+
+	parsed, raw, err := c.get(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	result := rdapToWhoisResult(domain, parsed, url, raw)
+
+	for _, link := range parsed.Links {
+		if !strings.EqualFold(link.Rel, "related") || link.Href == "" {
+			continue
+		}
+		if referral, referralRaw, err := c.get(ctx, link.Href); err == nil {
+			mergeReferral(result, rdapToWhoisResult(domain, referral, link.Href, referralRaw))
+		}
+		break
+	}
+
+	return result, nil
+}
+
+// get fetches and JSON-decodes an RDAP object, translating HTTP 404 into
+// ErrNoRDAPService so callers can fall back to WHOIS.
+func (c *RDAPClient) get(ctx context.Context, url string) (*rdapDomainResponse, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, raw, ErrNoRDAPService
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, raw, fmt.Errorf("rdap: unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	var parsed rdapDomainResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, raw, fmt.Errorf("rdap: failed to parse response from %s: %w", url, err)
+	}
+	return &parsed, raw, nil
+}
+
+// LookupIP performs an RDAP lookup for ip, resolving the authoritative RDAP
+// service from the IANA ipv4/ipv6 bootstrap registry (RFC 9224). Unlike
+// LookupDomain there is no legacy WHOIS fallback for this method: it is only
+// meaningful under RDAP.
+func (c *RDAPClient) LookupIP(ctx context.Context, ip string) (*WhoisResult, error) {
+	parsedIP := net.ParseIP(ip)
+	if parsedIP == nil {
+		return nil, fmt.Errorf("rdap: invalid IP address %q", ip)
+	}
+
+	bootstrapURL, cache := ianaBootstrapIPv4, c.ipv4Bootstrap
+	if parsedIP.To4() == nil {
+		bootstrapURL, cache = ianaBootstrapIPv6, c.ipv6Bootstrap
+	}
+
+	registry, err := cache.get(ctx, c.httpClient, bootstrapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := baseURLForIP(registry, parsedIP)
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSuffix(base, "/") + "/ip/" + ip
+	parsed, raw, err := c.getNetwork(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return rdapNetworkToWhoisResult(ip, parsed, url, raw), nil
+}
+
+// LookupASN performs an RDAP lookup for an autonomous system number, which
+// may be given as a bare number or with an "AS" prefix (e.g. "AS64512"),
+// resolving the authoritative RDAP service from the IANA asn.json bootstrap
+// registry. Like LookupIP, there is no legacy WHOIS fallback.
+func (c *RDAPClient) LookupASN(ctx context.Context, asn string) (*WhoisResult, error) {
+	digits := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(asn)), "AS")
+	asnNum, err := strconv.ParseUint(digits, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("rdap: invalid AS number %q", asn)
+	}
+
+	registry, err := c.asnBootstrap.get(ctx, c.httpClient, ianaBootstrapASN)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := baseURLForASN(registry, asnNum)
+	if err != nil {
+		return nil, err
+	}
+
+	url := strings.TrimSuffix(base, "/") + "/autnum/" + digits
+	parsed, raw, err := c.getNetwork(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return rdapNetworkToWhoisResult("AS"+digits, parsed, url, raw), nil
+}
+
+// baseURLForIP returns the RDAP base URL whose bootstrap CIDR range
+// contains ip.
+func baseURLForIP(registry *bootstrapRegistry, ip net.IP) (string, error) {
+	for _, svc := range registry.Services {
+		if len(svc) != 2 {
+			continue
+		}
+		for _, cidr := range svc[0] {
+			_, network, err := net.ParseCIDR(cidr)
+			if err != nil || network == nil {
+				continue
+			}
+			if network.Contains(ip) {
+				return firstNonEmpty(svc[1])
+			}
+		}
+	}
+	return "", ErrNoRDAPService
+}
+
+// baseURLForASN returns the RDAP base URL whose bootstrap range ("low-high"
+// or a single number) contains asn.
+func baseURLForASN(registry *bootstrapRegistry, asn uint64) (string, error) {
+	for _, svc := range registry.Services {
+		if len(svc) != 2 {
+			continue
+		}
+		for _, r := range svc[0] {
+			low, high, ok := parseASNRange(r)
+			if !ok {
+				continue
+			}
+			if asn >= low && asn <= high {
+				return firstNonEmpty(svc[1])
+			}
+		}
+	}
+	return "", ErrNoRDAPService
+}
+
+// parseASNRange parses a bootstrap ASN range such as "1876" or "1877-1899".
+func parseASNRange(r string) (low, high uint64, ok bool) {
+	parts := strings.SplitN(r, "-", 2)
+	low, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	if len(parts) == 1 {
+		return low, low, true
+	}
+	high, err = strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return low, high, true
+}
+
+// firstNonEmpty returns the first usable entry of a bootstrap base URL list,
+// or ErrNoRDAPService if it has no non-empty entry.
+func firstNonEmpty(urls []string) (string, error) {
+	for _, u := range urls {
+		if u != "" {
+			return u, nil
+		}
+	}
+	return "", ErrNoRDAPService
+}
+
+// rdapNetworkResponse mirrors the fields shared by RDAP "ip network" (RFC
+// 9083 section 5.4) and "autnum" (section 5.5) objects that this client maps
+// onto WhoisResult.
+type rdapNetworkResponse struct {
+	Handle   string       `json:"handle"`
+	Name     string       `json:"name"`
+	Country  string       `json:"country"`
+	Status   []string     `json:"status"`
+	Events   []rdapEvent  `json:"events"`
+	Entities []rdapEntity `json:"entities"`
+	Links    []rdapLink   `json:"links"`
+}
+
+// getNetwork fetches and JSON-decodes an RDAP ip/autnum object, translating
+// HTTP 404 into ErrNoRDAPService like get does for domains.
+func (c *RDAPClient) getNetwork(ctx context.Context, url string) (*rdapNetworkResponse, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, raw, ErrNoRDAPService
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, raw, fmt.Errorf("rdap: unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	var parsed rdapNetworkResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		return nil, raw, fmt.Errorf("rdap: failed to parse response from %s: %w", url, err)
+	}
+	return &parsed, raw, nil
+}
+
+// rdapNetworkToWhoisResult normalizes an RDAP ip/autnum object into the same
+// WhoisResult shape LookupDomain produces. There's no per-TLD registrar
+// concept for a network, so Registrar carries the network/AS name instead
+// (e.g. "APNIC-SINGAPORE" or "GOOGLE"); a dedicated result type with
+// CIDR/origin/RIR fields can be layered on top of this later.
+func rdapNetworkToWhoisResult(identifier string, resp *rdapNetworkResponse, server string, raw []byte) *WhoisResult {
+	result := &WhoisResult{
+		Domain:      identifier,
+		Server:      server,
+		Timestamp:   time.Now(),
+		RawResponse: string(raw),
+		Status:      resp.Status,
+		Registrar:   resp.Name,
+		ParsedData:  make(map[string]interface{}),
+	}
+
+	for _, ev := range resp.Events {
+		switch ev.Action {
+		case "registration":
+			result.CreatedDate = ev.Date
+		case "expiration":
+			result.ExpiryDate = ev.Date
+		case "last changed":
+			result.UpdatedDate = ev.Date
+		}
+	}
+
+	emailSeen := make(map[string]bool)
+	for _, e := range resp.Entities {
+		for _, email := range entityEmails(e) {
+			if !emailSeen[email] {
+				result.Emails = append(result.Emails, email)
+				emailSeen[email] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// rdapToWhoisResult normalizes an RDAP domain object into the same
+// WhoisResult shape the legacy WHOIS client produces.
+func rdapToWhoisResult(domain string, resp *rdapDomainResponse, server string, raw []byte) *WhoisResult {
+	result := &WhoisResult{
+		Domain:      domain,
+		Server:      server,
+		Timestamp:   time.Now(),
+		RawResponse: string(raw),
+		Status:      resp.Status,
+		ParsedData:  make(map[string]interface{}),
+	}
+
+	for _, ns := range resp.Nameservers {
+		if ns.LDHName != "" {
+			result.NameServers = append(result.NameServers, strings.ToLower(ns.LDHName))
+		}
+	}
+
+	for _, ev := range resp.Events {
+		switch ev.Action {
+		case "registration":
+			result.CreatedDate = ev.Date
+		case "expiration":
+			result.ExpiryDate = ev.Date
+		case "last changed":
+			result.UpdatedDate = ev.Date
+		}
+	}
+
+	emailSeen := make(map[string]bool)
+	for _, e := range resp.Entities {
+		if result.Registrar == "" && hasRole(e.Roles, "registrar") {
+			result.Registrar = entityName(e)
+		}
+		for _, email := range entityEmails(e) {
+			if !emailSeen[email] {
+				result.Emails = append(result.Emails, email)
+				emailSeen[email] = true
+			}
+		}
+	}
+
+	return result
+}
+
+// mergeReferral fills in gaps in result from a registrar RDAP referral,
+// preferring the referral's raw response when it's more complete, mirroring
+// how the legacy WHOIS client prefers a longer referral response.
+func mergeReferral(result, referral *WhoisResult) {
+	if result.Registrar == "" {
+		result.Registrar = referral.Registrar
+	}
+	if len(referral.Emails) > 0 {
+		result.Emails = append(result.Emails, referral.Emails...)
+	}
+	if len(referral.RawResponse) > len(result.RawResponse) {
+		result.RawResponse = referral.RawResponse
+		result.Server = referral.Server
+	}
+}
+
+// hasRole reports whether roles contains role, case-insensitively.
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// entityName extracts the "fn" (formatted name) property from an RDAP
+// entity's jCard vcardArray (RFC 7095).
+func entityName(e rdapEntity) string {
+	for _, name := range vcardProperty(e.VCardArray, "fn") {
+		return name
+	}
+	return ""
+}
+
+// entityEmails extracts every "email" property from an RDAP entity's jCard
+// vcardArray.
+func entityEmails(e rdapEntity) []string {
+	return vcardProperty(e.VCardArray, "email")
+}
+
+// vcardProperty returns the text values of every jCard property named prop
+// within vcardArray, which has the shape ["vcard", [[name, params, type,
+// value], ...]].
+func vcardProperty(vcardArray []interface{}, prop string) []string {
+	var values []string
+	if len(vcardArray) != 2 {
+		return values
+	}
+	props, ok := vcardArray[1].([]interface{})
+	if !ok {
+		return values
+	}
+	for _, p := range props {
+		entry, ok := p.([]interface{})
+		if !ok || len(entry) < 4 {
+			continue
+		}
+		name, _ := entry[0].(string)
+		if !strings.EqualFold(name, prop) {
+			continue
+		}
+		if value, ok := entry[3].(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// lastLabel returns the final label of domain ("www.example.com" -> "com").
+func lastLabel(domain string) string {
+	idx := strings.LastIndex(domain, ".")
+	if idx < 0 {
+		return domain
+	}
+	return domain[idx+1:]
+}
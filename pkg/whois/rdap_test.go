@@ -0,0 +1,154 @@
+package whois
+
+import (
+	"encoding/json"
+	"net"
+	"testing"
+)
+
+func TestRDAPToWhoisResult(t *testing.T) {
+	raw := []byte(`{
+		"ldhName": "example.com",
+		"status": ["active"],
+		"nameservers": [{"ldhName": "NS1.EXAMPLE.COM"}, {"ldhName": "ns2.example.com"}],
+		"events": [
+			{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+			{"eventAction": "expiration", "eventDate": "2030-08-13T04:00:00Z"}
+		],
+		"entities": [
+			{
+				"roles": ["registrar"],
+				"vcardArray": ["vcard", [
+					["version", {}, "text", "4.0"],
+					["fn", {}, "text", "Example Registrar Inc."],
+					["email", {}, "text", "abuse@example-registrar.test"]
+				]]
+			}
+		]
+	}`)
+
+	var parsed rdapDomainResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal fixture: %v", err)
+	}
+
+	result := rdapToWhoisResult("example.com", &parsed, "https://rdap.example/domain/example.com", raw)
+
+	if result.Registrar != "Example Registrar Inc." {
+		t.Errorf("Registrar = %q, want %q", result.Registrar, "Example Registrar Inc.")
+	}
+	if result.CreatedDate != "1995-08-14T04:00:00Z" {
+		t.Errorf("CreatedDate = %q, want registration event date", result.CreatedDate)
+	}
+	if result.ExpiryDate != "2030-08-13T04:00:00Z" {
+		t.Errorf("ExpiryDate = %q, want expiration event date", result.ExpiryDate)
+	}
+	if len(result.NameServers) != 2 || result.NameServers[0] != "ns1.example.com" {
+		t.Errorf("NameServers = %v, want lowercased [ns1.example.com ns2.example.com]", result.NameServers)
+	}
+	if len(result.Emails) != 1 || result.Emails[0] != "abuse@example-registrar.test" {
+		t.Errorf("Emails = %v, want [abuse@example-registrar.test]", result.Emails)
+	}
+}
+
+func TestVcardProperty(t *testing.T) {
+	vcard := []interface{}{
+		"vcard",
+		[]interface{}{
+			[]interface{}{"fn", map[string]interface{}{}, "text", "Jane Doe"},
+			[]interface{}{"email", map[string]interface{}{}, "text", "jane@example.test"},
+		},
+	}
+
+	if got := vcardProperty(vcard, "fn"); len(got) != 1 || got[0] != "Jane Doe" {
+		t.Errorf("vcardProperty(fn) = %v, want [Jane Doe]", got)
+	}
+	if got := vcardProperty(vcard, "missing"); len(got) != 0 {
+		t.Errorf("vcardProperty(missing) = %v, want empty", got)
+	}
+}
+
+func TestRdapNetworkToWhoisResult(t *testing.T) {
+	raw := []byte(`{
+		"handle": "NET-192-0-2-0-1",
+		"name": "EXAMPLE-NET",
+		"status": ["active"],
+		"events": [{"eventAction": "last changed", "eventDate": "2020-01-01T00:00:00Z"}],
+		"entities": [
+			{"roles": ["abuse"], "vcardArray": ["vcard", [["email", {}, "text", "abuse@example-net.test"]]]}
+		]
+	}`)
+
+	var parsed rdapNetworkResponse
+	if err := json.Unmarshal(raw, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal fixture: %v", err)
+	}
+
+	result := rdapNetworkToWhoisResult("192.0.2.1", &parsed, "https://rdap.example/ip/192.0.2.1", raw)
+
+	if result.Registrar != "EXAMPLE-NET" {
+		t.Errorf("Registrar = %q, want %q", result.Registrar, "EXAMPLE-NET")
+	}
+	if result.UpdatedDate != "2020-01-01T00:00:00Z" {
+		t.Errorf("UpdatedDate = %q, want last changed event date", result.UpdatedDate)
+	}
+	if len(result.Emails) != 1 || result.Emails[0] != "abuse@example-net.test" {
+		t.Errorf("Emails = %v, want [abuse@example-net.test]", result.Emails)
+	}
+}
+
+func TestBaseURLForIP(t *testing.T) {
+	registry := &bootstrapRegistry{
+		Services: [][][]string{
+			{{"192.0.2.0/24"}, {"https://rdap.example-a.test/"}},
+			{{"198.51.100.0/24"}, {"https://rdap.example-b.test/"}},
+		},
+	}
+
+	got, err := baseURLForIP(registry, net.ParseIP("198.51.100.7"))
+	if err != nil {
+		t.Fatalf("baseURLForIP() error = %v", err)
+	}
+	if got != "https://rdap.example-b.test/" {
+		t.Errorf("baseURLForIP() = %q, want %q", got, "https://rdap.example-b.test/")
+	}
+
+	if _, err := baseURLForIP(registry, net.ParseIP("203.0.113.1")); err != ErrNoRDAPService {
+		t.Errorf("baseURLForIP() for unmatched IP error = %v, want ErrNoRDAPService", err)
+	}
+}
+
+func TestBaseURLForASN(t *testing.T) {
+	registry := &bootstrapRegistry{
+		Services: [][][]string{
+			{{"1-1876"}, {"https://rdap.example-a.test/"}},
+			{{"65536"}, {"https://rdap.example-b.test/"}},
+		},
+	}
+
+	got, err := baseURLForASN(registry, 65536)
+	if err != nil {
+		t.Fatalf("baseURLForASN() error = %v", err)
+	}
+	if got != "https://rdap.example-b.test/" {
+		t.Errorf("baseURLForASN() = %q, want %q", got, "https://rdap.example-b.test/")
+	}
+
+	if _, err := baseURLForASN(registry, 999999); err != ErrNoRDAPService {
+		t.Errorf("baseURLForASN() for unmatched ASN error = %v, want ErrNoRDAPService", err)
+	}
+}
+
+func TestLastLabel(t *testing.T) {
+	tests := map[string]string{
+		"example.com":     "com",
+		"www.example.com": "com",
+		"co.uk":           "uk",
+		"localhost":       "localhost",
+	}
+	for domain, want := range tests {
+		if got := lastLabel(domain); got != want {
+			t.Errorf("lastLabel(%q) = %q, want %q", domain, got, want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package whois
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -85,8 +86,33 @@ type Client struct {
 	timeout        time.Duration
 	followReferral bool
 	servers        map[string]WhoisServer
+	rdap           *RDAPClient
+	useRDAP        RDAPMode
+	parsers        *ParserRegistry
+	cache          *resultCache
 }
 
+// RDAPMode controls how Client.Lookup uses RDAP (RFC 7480-7484) relative to
+// legacy WHOIS.
+type RDAPMode string
+
+const (
+	// RDAPModeOff never attempts RDAP; Lookup goes straight to legacy WHOIS.
+	// This is the default when Config.UseRDAP is left unset, preserving the
+	// pre-RDAP behavior for existing callers.
+	RDAPModeOff RDAPMode = ""
+	// RDAPModeAuto tries RDAP first and silently falls back to legacy WHOIS
+	// on any RDAP error, including network failures.
+	RDAPModeAuto RDAPMode = "auto"
+	// RDAPModePrefer tries RDAP first but only falls back to legacy WHOIS
+	// when the domain has no RDAP service registered (ErrNoRDAPService);
+	// other errors (e.g. a network failure reaching the RDAP server) are
+	// returned directly instead of being masked by a WHOIS retry.
+	RDAPModePrefer RDAPMode = "prefer"
+	// RDAPModeOnly uses RDAP exclusively and never falls back to WHOIS.
+	RDAPModeOnly RDAPMode = "only"
+)
+
 // Config contains configuration options for the WHOIS client
 type Config struct {
 	// Timeout for WHOIS queries (default: 10 seconds)
@@ -95,6 +121,15 @@ type Config struct {
 	FollowReferral bool
 	// CustomServers allows overriding default WHOIS servers
 	CustomServers map[string]WhoisServer
+	// UseRDAP controls how Lookup uses RDAP relative to legacy WHOIS: "auto"
+	// tries RDAP and falls back to WHOIS on any error, "prefer" only falls
+	// back when the TLD has no RDAP service, and "only" never falls back.
+	// Left unset (RDAPModeOff), Lookup never attempts RDAP.
+	UseRDAP RDAPMode
+	// Cache enables the optional result cache, keyed by domain. WHOIS
+	// responses carry no TTL, so every entry is cached for
+	// CacheConfig.DefaultTTL regardless of which lookup path produced it.
+	Cache CacheConfig
 }
 
 // DefaultConfig returns a default configuration
@@ -125,15 +160,84 @@ func NewClient(config *Config) (*Client, error) {
 		servers[k] = v
 	}
 
-	return &Client{
+	client := &Client{
 		timeout:        config.Timeout,
 		followReferral: config.FollowReferral,
 		servers:        servers,
-	}, nil
+		rdap:           NewRDAPClient(config.Timeout),
+		useRDAP:        config.UseRDAP,
+		parsers:        NewParserRegistry(),
+	}
+
+	if config.Cache.Enabled {
+		client.cache = newResultCache(config.Cache)
+	}
+
+	return client, nil
 }
 
-// Lookup performs a WHOIS lookup for the specified domain
+// Lookup performs a lookup for the specified domain. Its use of RDAP versus
+// legacy WHOIS is controlled by Config.UseRDAP; see RDAPMode. When
+// Config.Cache is enabled, repeated lookups for the same domain within
+// CacheConfig.DefaultTTL are served from cache; concurrent callers for the
+// same domain collapse into a single upstream query.
+// WhoisResult.ParsedData["cache"] reports "hit" or "miss".
 func (c *Client) Lookup(ctx context.Context, domain string) (*WhoisResult, error) {
+	if c.cache == nil {
+		return c.lookupDispatch(ctx, domain)
+	}
+
+	key := strings.ToLower(strings.TrimSpace(domain))
+	result, err, hit := c.cache.lookup(key, func() (*WhoisResult, error) {
+		return c.lookupDispatch(ctx, domain)
+	})
+	if result != nil {
+		if hit {
+			result.ParsedData["cache"] = "hit"
+		} else {
+			result.ParsedData["cache"] = "miss"
+		}
+	}
+	return result, err
+}
+
+// lookupDispatch performs the actual RDAP-vs-legacy-WHOIS dispatch for
+// Lookup, bypassing the cache.
+func (c *Client) lookupDispatch(ctx context.Context, domain string) (*WhoisResult, error) {
+	switch c.useRDAP {
+	case RDAPModeOnly:
+		return c.rdap.LookupDomain(ctx, domain)
+	case RDAPModePrefer:
+		result, err := c.rdap.LookupDomain(ctx, domain)
+		if err == nil || !errors.Is(err, ErrNoRDAPService) {
+			return result, err
+		}
+	case RDAPModeAuto:
+		if result, err := c.rdap.LookupDomain(ctx, domain); err == nil {
+			return result, nil
+		}
+	}
+	return c.lookupWHOIS(ctx, domain)
+}
+
+// LookupIPRDAP performs an RDAP lookup for an IP address. There is no legacy
+// WHOIS fallback for this method (see RDAPClient.LookupIP); it is only
+// meaningful under RDAP. See LookupIP for the legacy WHOIS equivalent with
+// RIR referral routing.
+func (c *Client) LookupIPRDAP(ctx context.Context, ip string) (*WhoisResult, error) {
+	return c.rdap.LookupIP(ctx, ip)
+}
+
+// LookupASNRDAP performs an RDAP lookup for an autonomous system number.
+// There is no legacy WHOIS fallback for this method (see
+// RDAPClient.LookupASN); it is only meaningful under RDAP. See LookupASN for
+// the legacy WHOIS equivalent with RIR referral routing.
+func (c *Client) LookupASNRDAP(ctx context.Context, asn string) (*WhoisResult, error) {
+	return c.rdap.LookupASN(ctx, asn)
+}
+
+// lookupWHOIS performs a legacy WHOIS lookup for the specified domain.
+func (c *Client) lookupWHOIS(ctx context.Context, domain string) (*WhoisResult, error) {
 	if ctx == nil {
 		var cancel context.CancelFunc
 		ctx, cancel = context.WithTimeout(context.Background(), c.timeout)
@@ -181,8 +285,12 @@ func (c *Client) Lookup(ctx context.Context, domain string) (*WhoisResult, error
 		}
 	}
 
-	// Parse the response
-	c.parseResponse(result)
+	// Parse the response with whichever Parser matches this server/TLD,
+	// falling back to the generic parser if it errors.
+	parser := c.parsers.ParserFor(result.Server, lastLabel(domain))
+	if err := parser.Parse(result.RawResponse, result); err != nil {
+		genericParser{}.Parse(result.RawResponse, result)
+	}
 
 	return result, nil
 }
@@ -230,6 +338,14 @@ func (c *Client) query(ctx context.Context, domain string, server WhoisServer) (
 	return response.String(), nil
 }
 
+// ServerFor returns the WHOIS server that would be queried for domain,
+// without performing a lookup. Callers that need to rate-limit or batch
+// queries per server (e.g. lookup.Client.LookupBatch) can use this to group
+// domains by server ahead of time.
+func (c *Client) ServerFor(domain string) (WhoisServer, error) {
+	return c.getWhoisServer(strings.ToLower(strings.TrimSpace(domain)))
+}
+
 // getWhoisServer determines the appropriate WHOIS server for a domain
 func (c *Client) getWhoisServer(domain string) (WhoisServer, error) {
 	parts := strings.Split(domain, ".")
@@ -281,111 +397,13 @@ func (c *Client) extractReferralServer(response string) string {
 	return ""
 }
 
-// parseResponse parses the WHOIS response and extracts structured data
+// parseResponse parses the WHOIS response using the generic key/value
+// parser, kept as a thin wrapper for callers that want the
+// registry-independent fallback behavior directly. Lookup itself goes
+// through c.parsers.ParserFor so TLD-specific registries get a dedicated
+// Parser (see parsers.go).
 func (c *Client) parseResponse(result *WhoisResult) {
-	lines := strings.Split(result.RawResponse, "\n")
-
-	var nameServers []string
-	var status []string
-	var emails []string
-
-	emailMap := make(map[string]bool)
-	nsMap := make(map[string]bool)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "%") || strings.HasPrefix(line, "#") {
-			continue
-		}
-
-		lower := strings.ToLower(line)
-
-		// Extract registrar
-		if result.Registrar == "" && (strings.Contains(lower, "registrar:") || strings.Contains(lower, "registrar name:")) {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				result.Registrar = strings.TrimSpace(parts[1])
-			}
-		}
-
-		// Extract dates
-		if result.CreatedDate == "" && (strings.Contains(lower, "creation date:") || strings.Contains(lower, "created:") || strings.Contains(lower, "registered:")) {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				result.CreatedDate = strings.TrimSpace(parts[1])
-			}
-		}
-
-		if result.ExpiryDate == "" && (strings.Contains(lower, "expiry date:") || strings.Contains(lower, "expiration date:") || strings.Contains(lower, "expires:")) {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				result.ExpiryDate = strings.TrimSpace(parts[1])
-			}
-		}
-
-		if result.UpdatedDate == "" && (strings.Contains(lower, "updated date:") || strings.Contains(lower, "last updated:") || strings.Contains(lower, "modified:")) {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				result.UpdatedDate = strings.TrimSpace(parts[1])
-			}
-		}
-
-		// Extract name servers
-		if strings.Contains(lower, "name server:") || strings.Contains(lower, "nserver:") || strings.Contains(lower, "nameserver:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				ns := strings.TrimSpace(parts[1])
-				ns = strings.ToLower(ns)
-				// Remove any additional info after whitespace
-				if idx := strings.Index(ns, " "); idx > 0 {
-					ns = ns[:idx]
-				}
-				if !nsMap[ns] {
-					nameServers = append(nameServers, ns)
-					nsMap[ns] = true
-				}
-			}
-		}
-
-		// Extract status
-		if strings.Contains(lower, "status:") || strings.Contains(lower, "domain status:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				statusValue := strings.TrimSpace(parts[1])
-				if statusValue != "" {
-					status = append(status, statusValue)
-				}
-			}
-		}
-
-		// Extract emails
-		if strings.Contains(line, "@") {
-			// Simple email extraction
-			words := strings.Fields(line)
-			for _, word := range words {
-				if strings.Contains(word, "@") && strings.Contains(word, ".") {
-					email := strings.Trim(word, ",:;()<>[]")
-					if !emailMap[email] && isValidEmail(email) {
-						emails = append(emails, email)
-						emailMap[email] = true
-					}
-				}
-			}
-		}
-	}
-
-	result.NameServers = nameServers
-	result.Status = status
-	result.Emails = emails
-
-	// Store in parsed data as well
-	result.ParsedData["registrar"] = result.Registrar
-	result.ParsedData["created_date"] = result.CreatedDate
-	result.ParsedData["expiry_date"] = result.ExpiryDate
-	result.ParsedData["updated_date"] = result.UpdatedDate
-	result.ParsedData["name_servers"] = result.NameServers
-	result.ParsedData["status"] = result.Status
-	result.ParsedData["emails"] = result.Emails
+	genericParser{}.Parse(result.RawResponse, result)
 }
 
 // isValidEmail performs basic email validation